@@ -1,13 +1,13 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
+	"context"
 	"log"
-
-	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
 )
@@ -20,309 +20,314 @@ type Client struct {
 	Api_key      string
 	Auth_token   string
 	Api_endpoint string
+	Location     string
 	HttpClient   *http.Client
+	Max_retries  int
+	Max_backoff  time.Duration
 }
 
-func NewClient(api_key string, auth_token string, api_endpoint string) *Client {
+func NewClient(api_key string, auth_token string, api_endpoint string, location string) *Client {
+	if !strings.HasSuffix(api_endpoint, "/") {
+		api_endpoint = api_endpoint + "/"
+	}
 	return &Client{
 
 		Api_key:      api_key,
 		Auth_token:   auth_token,
 		Api_endpoint: api_endpoint,
+		Location:     location,
 		HttpClient:   &http.Client{},
+		Max_retries:  DefaultMaxRetries,
+		Max_backoff:  DefaultMaxBackoff,
 	}
 }
 
-func (c *Client) NewNode(item *models.Node) (map[string]interface{}, error) {
-
-	buf := bytes.Buffer{}
-	err := json.NewEncoder(&buf).Encode(item)
-	if err != nil {
-		return nil, err
-	}
-	UrlNode := c.Api_endpoint + "nodes/"
-	log.Printf("[INFO] %s", UrlNode)
-	req, err := http.NewRequest("POST", UrlNode, &buf)
-	if err != nil {
-		return nil, err
+// ForLocation returns a copy of the client that sends its requests to
+// location. The copy shares the underlying HTTP client.
+func (c *Client) ForLocation(location string) *Client {
+	if location == "" || location == c.Location {
+		return c
 	}
+	locationClient := *c
+	locationClient.Location = location
+	return &locationClient
+}
 
-	params := req.URL.Query()
-
-	params.Add("apikey", c.Api_key)
-	req.URL.RawQuery = params.Encode()
-	req.Header.Add("Authorization", "Bearer "+c.Auth_token)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("User-Agent", "terraform-e2e")
-	response, err := c.HttpClient.Do(req)
+func (c *Client) NewNode(ctx context.Context, item *models.Node) (*models.NodeResponse, error) {
 
+	log.Printf("[INFO] creating node %s", item.Name)
+	res := models.NodeResponse{}
+	err := c.doRequest(ctx, http.MethodPost, "nodes/", nil, item, &res)
 	if err != nil {
 		return nil, err
 	}
-	defer response.Body.Close()
-	resBody, _ := ioutil.ReadAll(response.Body)
-	stringresponse := string(resBody)
-	resBytes := []byte(stringresponse)
-	var jsonRes map[string]interface{}
-	err = json.Unmarshal(resBytes, &jsonRes)
-	if err != nil {
-		return nil, err
-	}
-
-	return jsonRes, nil
-
+	return &res, nil
 }
 
-func (c *Client) GetNode(nodeId string) (map[string]interface{}, error) {
+func (c *Client) GetNode(ctx context.Context, nodeId string) (*models.NodeDetail, error) {
 
-	urlNode := c.Api_endpoint + "nodes/" + nodeId + "/"
-	req, err := http.NewRequest("GET", urlNode, nil)
+	params := url.Values{}
+	params.Add("contact_person_id", "null")
+	res := models.NodeResponse{}
+	err := c.doRequest(ctx, http.MethodGet, "nodes/"+nodeId+"/", params, nil, &res)
 	if err != nil {
 		return nil, err
 	}
-	log.Printf("[INFO] NEW BUILD READ")
-	params := req.URL.Query()
+	return &res.Data, nil
+}
 
-	params.Add("apikey", c.Api_key)
-	params.Add("contact_person_id", "null")
-	req.URL.RawQuery = params.Encode()
-	req.Header.Add("Authorization", "Bearer "+c.Auth_token)
-	req.Header.Add("Content-Type", "application/json")
+const nodesPerPage = 100
 
-	req.Header.Add("User-Agent", "terraform-e2e")
+// ListNodes returns every node of the location, following pagination.
+func (c *Client) ListNodes(ctx context.Context) ([]models.NodeDetail, error) {
 
-	response, err := c.HttpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	if response.StatusCode != http.StatusOK {
-		respBody := new(bytes.Buffer)
-		_, err := respBody.ReadFrom(response.Body)
+	nodes := []models.NodeDetail{}
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Add("page_no", strconv.Itoa(page))
+		params.Add("per_page", strconv.Itoa(nodesPerPage))
+		res := models.NodeListResponse{}
+		err := c.doRequest(ctx, http.MethodGet, "nodes/", params, nil, &res)
 		if err != nil {
-			return nil, fmt.Errorf("got a non 200 status code: %v", response.StatusCode)
+			return nil, err
+		}
+		nodes = append(nodes, res.Data...)
+		if page >= res.Total_page_number || len(res.Data) == 0 {
+			return nodes, nil
 		}
-		return nil, fmt.Errorf("got a non 200 status code: %v - %s", response.StatusCode, respBody.String())
-	}
-	fmt.Println(response.Body)
-
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close()
-	resBody, _ := ioutil.ReadAll(response.Body)
-	stringresponse := string(resBody)
-	resBytes := []byte(stringresponse)
-	var jsonRes map[string]interface{}
-
-	err = json.Unmarshal(resBytes, &jsonRes)
-	if err != nil {
-		return nil, err
 	}
-
-	return jsonRes, nil
 }
 
-func (c *Client) UpdateNode(nodeId string, action string, nodeName string) (interface{}, error) {
+func (c *Client) UpdateNode(ctx context.Context, nodeId string, action string, nodeName string) (*models.ActionDetail, error) {
 
 	node_action := models.NodeAction{
 		Type: action,
 		Name: nodeName,
 	}
-	nodeAction, err := json.Marshal(node_action)
-	url := c.Api_endpoint + "nodes/" + nodeId + "/actions/"
-	log.Printf("[info] %s", url)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(nodeAction))
+	log.Printf("[INFO] node %s action %s", nodeId, action)
+	res := models.ActionResponse{}
+	err := c.doRequest(ctx, http.MethodPost, "nodes/"+nodeId+"/actions/", nil, &node_action, &res)
 	if err != nil {
 		return nil, err
 	}
-	params := req.URL.Query()
-	params.Add("apikey", c.Api_key)
-	req.Header.Add("Authorization", "Bearer "+c.Auth_token)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("User-Agent", "terraform-e2e")
-	req.URL.RawQuery = params.Encode()
-	response, err := c.HttpClient.Do(req)
+	return &res.Data, nil
+}
 
-	if err != nil {
+// UpgradeNode moves a powered off node to another plan.
+func (c *Client) UpgradeNode(ctx context.Context, nodeId string, item *models.NodeUpgrade) (*models.NodeDetail, error) {
 
-		return nil, err
-	}
-	log.Printf("[INFO] inside update %s %d", action, response.StatusCode)
-	if response.StatusCode != http.StatusOK {
-		respBody := new(bytes.Buffer)
-		_, err := respBody.ReadFrom(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("got a non 200 status code: %v", response.StatusCode)
-		}
-		return nil, fmt.Errorf("got a non 200 status code: %v - %s", response.StatusCode, respBody.String())
-	}
-	defer response.Body.Close()
-	resBody, _ := ioutil.ReadAll(response.Body)
-	stringresponse := string(resBody)
-	resBytes := []byte(stringresponse)
-	var jsonRes map[string]interface{}
-	err = json.Unmarshal(resBytes, &jsonRes)
+	log.Printf("[INFO] upgrading node %s to plan %s", nodeId, item.Plan)
+	res := models.NodeResponse{}
+	err := c.doRequest(ctx, http.MethodPost, "nodes/upgrade/"+nodeId+"/", nil, item, &res)
 	if err != nil {
-		return jsonRes, err
+		return nil, err
 	}
-	return jsonRes, err
+	return &res.Data, nil
 }
 
-func (c *Client) DeleteNode(nodeId string) error {
-
-	urlNode := c.Api_endpoint + "nodes/" + nodeId + "/"
-	req, err := http.NewRequest("DELETE", urlNode, nil)
-	if err != nil {
-		return err
-	}
+func (c *Client) DeleteNode(ctx context.Context, nodeId string) error {
 
-	params := req.URL.Query()
-	params.Add("apikey", c.Api_key)
+	params := url.Values{}
 	params.Add("contact_person_id", "null")
-	req.URL.RawQuery = params.Encode()
-	req.Header.Add("Authorization", "Bearer "+c.Auth_token)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("User-Agent", "terraform-e2e")
-	response, err := c.HttpClient.Do(req)
-	if err != nil {
-		return err
+	return c.doRequest(ctx, http.MethodDelete, "nodes/"+nodeId+"/", params, nil, nil)
+}
+
+// GetPlans lists the node plans of the location. When image is set only the
+// plans that image can be launched on are returned.
+func (c *Client) GetPlans(ctx context.Context, image string) ([]models.Plan, error) {
+
+	params := url.Values{}
+	if image != "" {
+		params.Add("image", image)
 	}
-	if response.StatusCode != http.StatusOK {
-		respBody := new(bytes.Buffer)
-		_, err := respBody.ReadFrom(response.Body)
-		if err != nil {
-			return fmt.Errorf("got a non 200 status code: %v", response.StatusCode)
-		}
-		return fmt.Errorf("got a non 200 status code: %v - %s", response.StatusCode, respBody.String())
+	res := models.PlanListResponse{}
+	err := c.doRequest(ctx, http.MethodGet, "plans/", params, nil, &res)
+	if err != nil {
+		return nil, err
 	}
-
-	return nil
+	return res.Data, nil
 }
 
-func (c *Client) GetSavedImages() (*models.ImageListResponse, error) {
+// GetOsImages lists the public distribution images nodes can be launched from.
+func (c *Client) GetOsImages(ctx context.Context) ([]models.OsImage, error) {
 
-	urlImages := c.Api_endpoint + "images/" + "saved-images" + "/"
-
-	req, err := http.NewRequest("GET", urlImages, nil)
+	res := models.OsImageListResponse{}
+	err := c.doRequest(ctx, http.MethodGet, "images/os-category/", nil, nil, &res)
 	if err != nil {
 		return nil, err
 	}
+	return res.Data, nil
+}
 
-	params := req.URL.Query()
-	params.Add("apikey", c.Api_key)
+func (c *Client) GetSavedImages(ctx context.Context) (*models.ImageListResponse, error) {
+
+	params := url.Values{}
 	params.Add("contact_person_id", "null")
-	req.URL.RawQuery = params.Encode()
-	req.Header.Add("Authorization", "Bearer "+c.Auth_token)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("User-Agent", "terraform-e2e")
-	response, err := c.HttpClient.Do(req)
-	log.Printf("[INFO] inside client saved image before request hit")
+	res := models.ImageListResponse{}
+	err := c.doRequest(ctx, http.MethodGet, "images/saved-images/", params, nil, &res)
 	if err != nil {
-		log.Printf("[INFO] error inside get image")
+		log.Printf("[INFO] error inside get saved images")
 		return nil, err
 	}
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
-	res := models.ImageListResponse{}
-	err = json.Unmarshal(body, &res)
+	return &res, nil
+}
+
+func (c *Client) DeleteSavedImage(ctx context.Context, imageId string) error {
+
+	log.Printf("[INFO] deleting saved image %s", imageId)
+	return c.doRequest(ctx, http.MethodDelete, "images/"+imageId+"/", nil, nil, nil)
+}
+
+func (c *Client) GetSecurityGroups(ctx context.Context) (*models.SecurityGroupsResponse, error) {
+
+	res := models.SecurityGroupsResponse{}
+	err := c.doRequest(ctx, http.MethodGet, "security_group/", nil, nil, &res)
 	if err != nil {
+		log.Printf("[INFO] error inside get security groups")
 		return nil, err
 	}
 	return &res, nil
 }
-func (c *Client) GetSecurityGroups() (*models.SecurityGroupsResponse, error) {
 
-	urlSecurityGroups := c.Api_endpoint + "security_group/"
-	req, err := http.NewRequest("GET", urlSecurityGroups, nil)
+func (c *Client) GetSshKeys(ctx context.Context) (*models.SshKeyResponse, error) {
+
+	res := models.SshKeyResponse{}
+	err := c.doRequest(ctx, http.MethodGet, "ssh_keys/", nil, nil, &res)
 	if err != nil {
+		log.Printf("[INFO] error inside get ssh keys")
 		return nil, err
 	}
-	params := req.URL.Query()
-	params.Add("apikey", c.Api_key)
-	req.URL.RawQuery = params.Encode()
-	req.Header.Add("Authorization", "Bearer "+c.Auth_token)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("User-Agent", "terraform-e2e")
-	response, err := c.HttpClient.Do(req)
+	return &res, nil
+}
+
+func (c *Client) GetVpcs(ctx context.Context) (*models.VpcsResponse, error) {
+
+	res := models.VpcsResponse{}
+	err := c.doRequest(ctx, http.MethodGet, "vpc/list/", nil, nil, &res)
 	if err != nil {
-		log.Printf("[INFO] error inside get security groups")
+		log.Printf("[INFO] error inside get vpcs")
 		return nil, err
 	}
+	return &res, nil
+}
 
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
-	res := models.SecurityGroupsResponse{}
-	err = json.Unmarshal(body, &res)
+func (c *Client) CreateSshKey(ctx context.Context, item *models.AddSshKey) (*models.SshKey, error) {
+
+	res := models.SingleSshKeyResponse{}
+	err := c.doRequest(ctx, http.MethodPost, "ssh_keys/", nil, item, &res)
 	if err != nil {
-		log.Printf("[INFO] inside get security groups | error while unmarshlling")
 		return nil, err
 	}
-	return &res, nil
+	return &res.Data, nil
 }
 
-func (c *Client) GetSshKeys() (*models.SshKeyResponse, error) {
+func (c *Client) GetSshKey(ctx context.Context, pk string) (*models.SshKey, error) {
 
-	urlSshKeys := c.Api_endpoint + "ssh_keys/"
-	req, err := http.NewRequest("GET", urlSshKeys, nil)
+	res := models.SingleSshKeyResponse{}
+	err := c.doRequest(ctx, http.MethodGet, "ssh_keys/"+pk+"/", nil, nil, &res)
 	if err != nil {
 		return nil, err
 	}
+	return &res.Data, nil
+}
+
+func (c *Client) DeleteSshKey(ctx context.Context, pk string) error {
+
+	return c.doRequest(ctx, http.MethodDelete, "ssh_keys/"+pk+"/", nil, nil, nil)
+}
 
-	params := req.URL.Query()
-	params.Add("apikey", c.Api_key)
-	req.URL.RawQuery = params.Encode()
-	req.Header.Add("Authorization", "Bearer "+c.Auth_token)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("User-Agent", "terraform-e2e")
-	response, err := c.HttpClient.Do(req)
+func (c *Client) CreateSecurityGroup(ctx context.Context, item *models.AddSecurityGroup) (*models.SecurityGroup, error) {
+
+	res := models.SingleSecurityGroupResponse{}
+	err := c.doRequest(ctx, http.MethodPost, "security_group/", nil, item, &res)
 	if err != nil {
-		log.Printf("[INFO] error inside get ssh keys")
 		return nil, err
 	}
+	return &res.Data, nil
+}
 
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
-	res := models.SshKeyResponse{}
-	err = json.Unmarshal(body, &res)
+func (c *Client) GetSecurityGroup(ctx context.Context, securityGroupId string) (*models.SecurityGroup, error) {
+
+	res := models.SingleSecurityGroupResponse{}
+	err := c.doRequest(ctx, http.MethodGet, "security_group/"+securityGroupId+"/", nil, nil, &res)
 	if err != nil {
-		log.Printf("[INFO] inside get ssh_keys | error while unmarshlling")
 		return nil, err
 	}
-	return &res, nil
+	return &res.Data, nil
 }
 
-func (c *Client) GetVpcs() (*models.VpcsResponse, error) {
+// UpdateSecurityGroup replaces the name, description and the whole rule set
+// of a security group.
+func (c *Client) UpdateSecurityGroup(ctx context.Context, securityGroupId string, item *models.AddSecurityGroup) (*models.SecurityGroup, error) {
 
-	urlGetVpcs := c.Api_endpoint + "vpc/" + "list/"
-	req, err := http.NewRequest("GET", urlGetVpcs, nil)
+	res := models.SingleSecurityGroupResponse{}
+	err := c.doRequest(ctx, http.MethodPut, "security_group/"+securityGroupId+"/", nil, item, &res)
 	if err != nil {
 		return nil, err
 	}
+	return &res.Data, nil
+}
+
+func (c *Client) DeleteSecurityGroup(ctx context.Context, securityGroupId string) error {
+
+	return c.doRequest(ctx, http.MethodDelete, "security_group/"+securityGroupId+"/", nil, nil, nil)
+}
+
+// AttachSecurityGroups adds the security groups to the node. Groups already
+// attached stay attached.
+func (c *Client) AttachSecurityGroups(ctx context.Context, nodeId string, securityGroupIds []int) error {
+
+	log.Printf("[INFO] attaching security groups %v to node %s", securityGroupIds, nodeId)
+	item := models.NodeSecurityGroups{Security_group_ids: securityGroupIds}
+	return c.doRequest(ctx, http.MethodPost, "security_group/"+nodeId+"/attach/", nil, &item, nil)
+}
+
+// DetachSecurityGroups removes the security groups from the node. The API
+// refuses to detach the last group of a node.
+func (c *Client) DetachSecurityGroups(ctx context.Context, nodeId string, securityGroupIds []int) error {
 
-	params := req.URL.Query()
-	params.Add("apikey", c.Api_key)
+	log.Printf("[INFO] detaching security groups %v from node %s", securityGroupIds, nodeId)
+	item := models.NodeSecurityGroups{Security_group_ids: securityGroupIds}
+	return c.doRequest(ctx, http.MethodPost, "security_group/"+nodeId+"/detach/", nil, &item, nil)
+}
+
+// AttachVpc attaches the node to the vpc, giving it a private IP in the vpc.
+func (c *Client) AttachVpc(ctx context.Context, nodeId int, networkId int) error {
 
-	req.URL.RawQuery = params.Encode()
-	req.Header.Add("Authorization", "Bearer "+c.Auth_token)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("User-Agent", "terraform-e2e")
+	log.Printf("[INFO] attaching vpc %d to node %d", networkId, nodeId)
+	item := models.NodeVpcAction{Action: "attach", Network_id: networkId, Node_id: nodeId}
+	return c.doRequest(ctx, http.MethodPost, "vpc/node/attach/", nil, &item, nil)
+}
 
-	response, err := c.HttpClient.Do(req)
+// DetachVpc detaches the node from the vpc, releasing its private IP there.
+func (c *Client) DetachVpc(ctx context.Context, nodeId int, networkId int) error {
 
+	log.Printf("[INFO] detaching vpc %d from node %d", networkId, nodeId)
+	item := models.NodeVpcAction{Action: "detach", Network_id: networkId, Node_id: nodeId}
+	return c.doRequest(ctx, http.MethodPost, "vpc/node/attach/", nil, &item, nil)
+}
+
+func (c *Client) CreateVpc(ctx context.Context, item *models.AddVpc) (*models.Vpc, error) {
+
+	res := models.SingleVpcResponse{}
+	err := c.doRequest(ctx, http.MethodPost, "vpc/", nil, item, &res)
 	if err != nil {
-		log.Printf("[INFO] error inside get vpcs")
 		return nil, err
 	}
+	return &res.Data, nil
+}
 
-	defer response.Body.Close()
-
-	body, err := ioutil.ReadAll(response.Body)
-	res := models.VpcsResponse{}
+func (c *Client) GetVpc(ctx context.Context, networkId string) (*models.Vpc, error) {
 
-	err = json.Unmarshal(body, &res)
+	res := models.SingleVpcResponse{}
+	err := c.doRequest(ctx, http.MethodGet, "vpc/"+networkId+"/", nil, nil, &res)
 	if err != nil {
-		log.Printf("[INFO] inside get vpcs | error while unmarshlling")
 		return nil, err
 	}
-	return &res, nil
+	return &res.Data, nil
+}
+
+func (c *Client) DeleteVpc(ctx context.Context, networkId string) error {
+
+	return c.doRequest(ctx, http.MethodDelete, "vpc/"+networkId+"/", nil, nil, nil)
 }
@@ -0,0 +1,388 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+)
+
+func newTestClient(server *fakeapi.Server) *client.Client {
+	apiClient := client.NewClient(fakeapi.APIKey, fakeapi.AuthToken, server.Endpoint(), "Delhi")
+	apiClient.Max_backoff = 10 * time.Millisecond
+	return apiClient
+}
+
+func TestNodeLifecycle(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := newTestClient(server)
+	ctx := context.Background()
+
+	created, err := apiClient.NewNode(ctx, &models.Node{Name: "web-1", Label: "web", Plan: "C2.40GB", Image: "Ubuntu-22.04-Distro"})
+	if err != nil {
+		t.Fatalf("NewNode: %s", err)
+	}
+	if got := created.Data.Status; got != "Creating" {
+		t.Fatalf("expected new node to be Creating, got %s", got)
+	}
+	nodeId := strconv.Itoa(created.Data.Id)
+
+	for _, want := range []string{"Creating", "Running"} {
+		node, err := apiClient.GetNode(ctx, nodeId)
+		if err != nil {
+			t.Fatalf("GetNode: %s", err)
+		}
+		if got := node.Status; got != want {
+			t.Fatalf("expected status %s, got %s", want, got)
+		}
+	}
+
+	if _, err := apiClient.UpdateNode(ctx, nodeId, "lock_vm", ""); err != nil {
+		t.Fatalf("lock_vm: %s", err)
+	}
+	if _, err := apiClient.UpdateNode(ctx, nodeId, "power_off", "web-1"); err == nil {
+		t.Fatalf("expected power_off on a locked node to fail")
+	}
+	if err := apiClient.DeleteNode(ctx, nodeId); err == nil {
+		t.Fatalf("expected deleting a locked node to fail")
+	}
+	if _, err := apiClient.UpdateNode(ctx, nodeId, "unlock_vm", "web-1"); err != nil {
+		t.Fatalf("unlock_vm: %s", err)
+	}
+	if err := apiClient.DeleteNode(ctx, nodeId); err != nil {
+		t.Fatalf("DeleteNode: %s", err)
+	}
+
+	_, err = apiClient.GetNode(ctx, nodeId)
+	if !client.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestRequestsAreRoutedByLocation(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := newTestClient(server)
+	ctx := context.Background()
+
+	created, err := apiClient.NewNode(ctx, &models.Node{Name: "web-1", Plan: "C2.40GB", Image: "Ubuntu-22.04-Distro"})
+	if err != nil {
+		t.Fatalf("NewNode: %s", err)
+	}
+	nodeId := strconv.Itoa(created.Data.Id)
+
+	if _, err := apiClient.GetNode(ctx, nodeId); err != nil {
+		t.Fatalf("GetNode in Delhi: %s", err)
+	}
+	if _, err := apiClient.ForLocation("Mumbai").GetNode(ctx, nodeId); !client.IsNotFound(err) {
+		t.Fatalf("expected node to be missing in Mumbai, got %v", err)
+	}
+	if apiClient.Location != "Delhi" {
+		t.Fatalf("ForLocation modified the provider client")
+	}
+
+	requests := server.Requests()
+	if got := requests[0].URL.Query().Get("location"); got != "Delhi" {
+		t.Fatalf("expected location Delhi, got %q", got)
+	}
+	if got := requests[len(requests)-1].URL.Query().Get("location"); got != "Mumbai" {
+		t.Fatalf("expected location Mumbai, got %q", got)
+	}
+}
+
+func TestListNodesFollowsPagination(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := newTestClient(server)
+	ctx := context.Background()
+
+	for i := 0; i < 105; i++ {
+		if _, err := apiClient.NewNode(ctx, &models.Node{Name: "web-" + strconv.Itoa(i), Plan: "C2.40GB", Image: "Ubuntu-22.04-Distro"}); err != nil {
+			t.Fatalf("NewNode: %s", err)
+		}
+	}
+	if _, err := apiClient.ForLocation("Mumbai").NewNode(ctx, &models.Node{Name: "db-1", Plan: "C2.40GB", Image: "Ubuntu-22.04-Distro"}); err != nil {
+		t.Fatalf("NewNode in Mumbai: %s", err)
+	}
+
+	nodes, err := apiClient.ListNodes(ctx)
+	if err != nil {
+		t.Fatalf("ListNodes: %s", err)
+	}
+	if len(nodes) != 105 {
+		t.Fatalf("expected 105 nodes, got %d", len(nodes))
+	}
+	if nodes[104].Name != "web-104" {
+		t.Fatalf("expected last node web-104, got %s", nodes[104].Name)
+	}
+
+	pages := 0
+	for _, r := range server.Requests() {
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/nodes/") {
+			pages++
+		}
+	}
+	if pages != 2 {
+		t.Fatalf("expected 2 list requests, got %d", pages)
+	}
+}
+
+func TestAttachAndDetachSecurityGroups(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := newTestClient(server)
+	ctx := context.Background()
+
+	group, err := apiClient.CreateSecurityGroup(ctx, &models.AddSecurityGroup{Name: "web"})
+	if err != nil {
+		t.Fatalf("CreateSecurityGroup: %s", err)
+	}
+	created, err := apiClient.NewNode(ctx, &models.Node{Name: "web-1", Label: "web", Plan: "C2.40GB", Image: "Ubuntu-22.04-Distro", Security_group_id: 150})
+	if err != nil {
+		t.Fatalf("NewNode: %s", err)
+	}
+	nodeId := strconv.Itoa(created.Data.Id)
+
+	if err := apiClient.AttachSecurityGroups(ctx, nodeId, []int{int(group.Id)}); err != nil {
+		t.Fatalf("AttachSecurityGroups: %s", err)
+	}
+	if err := apiClient.DetachSecurityGroups(ctx, nodeId, []int{150}); err != nil {
+		t.Fatalf("DetachSecurityGroups: %s", err)
+	}
+	if err := apiClient.DetachSecurityGroups(ctx, nodeId, []int{int(group.Id)}); err == nil {
+		t.Fatalf("expected detaching the last security group to fail")
+	}
+
+	node, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		t.Fatalf("GetNode: %s", err)
+	}
+	if len(node.Security_groups) != 1 || node.Security_groups[0].Id != int(group.Id) || node.Security_group_id != int(group.Id) {
+		t.Fatalf("expected only security group %d to be attached, got %+v", int(group.Id), node.Security_groups)
+	}
+}
+
+func TestAttachAndDetachVpc(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := newTestClient(server)
+	ctx := context.Background()
+
+	created, err := apiClient.NewNode(ctx, &models.Node{Name: "web-1", Label: "web", Plan: "C2.40GB", Image: "Ubuntu-22.04-Distro"})
+	if err != nil {
+		t.Fatalf("NewNode: %s", err)
+	}
+	nodeId := created.Data.Id
+
+	if err := apiClient.AttachVpc(ctx, nodeId, 301); err != nil {
+		t.Fatalf("AttachVpc: %s", err)
+	}
+	if err := apiClient.AttachVpc(ctx, nodeId, 301); err == nil {
+		t.Fatalf("expected attaching the same vpc twice to fail")
+	}
+	node, err := apiClient.GetNode(ctx, strconv.Itoa(nodeId))
+	if err != nil {
+		t.Fatalf("GetNode: %s", err)
+	}
+	if node.Vpc_id != "301" || len(node.Vpcs) != 1 || node.Vpcs[0].Private_ip == "" {
+		t.Fatalf("expected node to be attached to vpc 301, got %s %+v", node.Vpc_id, node.Vpcs)
+	}
+
+	if err := apiClient.DetachVpc(ctx, nodeId, 301); err != nil {
+		t.Fatalf("DetachVpc: %s", err)
+	}
+	node, err = apiClient.GetNode(ctx, strconv.Itoa(nodeId))
+	if err != nil {
+		t.Fatalf("GetNode: %s", err)
+	}
+	if node.Vpc_id != "" || len(node.Vpcs) != 0 {
+		t.Fatalf("expected node to be detached, got %s %+v", node.Vpc_id, node.Vpcs)
+	}
+}
+
+func TestAPIErrorDoesNotLeakApiKey(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := newTestClient(server)
+
+	_, err := apiClient.GetNode(context.Background(), "42")
+	apiErr, ok := err.(*client.APIError)
+	if !ok {
+		t.Fatalf("expected *client.APIError, got %T", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Method != http.MethodGet {
+		t.Fatalf("unexpected error fields: %+v", apiErr)
+	}
+	if strings.Contains(err.Error(), fakeapi.APIKey) {
+		t.Fatalf("error message leaks the api key: %s", err)
+	}
+	if client.IsConflict(err) || client.IsRateLimited(err) {
+		t.Fatalf("not found error matched another kind: %s", err)
+	}
+}
+
+func TestRetriesTransientErrors(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := newTestClient(server)
+
+	server.FailNext(1, http.StatusTooManyRequests, "0")
+	server.FailNext(1, http.StatusBadGateway, "")
+	res, err := apiClient.GetVpcs(context.Background())
+	if err != nil {
+		t.Fatalf("GetVpcs: %s", err)
+	}
+	if len(res.Data) != 1 {
+		t.Fatalf("expected 1 vpc, got %d", len(res.Data))
+	}
+	if got := len(server.Requests()); got != 3 {
+		t.Fatalf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestRetriesAreBounded(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := newTestClient(server)
+	apiClient.Max_retries = 2
+
+	server.FailNext(5, http.StatusTooManyRequests, "0")
+	_, err := apiClient.GetSshKeys(context.Background())
+	if !client.IsRateLimited(err) {
+		t.Fatalf("expected rate limited error, got %v", err)
+	}
+	if got := len(server.Requests()); got != 3 {
+		t.Fatalf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestDoesNotRetryNonIdempotentServerErrors(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := newTestClient(server)
+
+	server.FailNext(1, http.StatusInternalServerError, "")
+	_, err := apiClient.NewNode(context.Background(), &models.Node{Name: "web-1", Plan: "C2.40GB", Image: "Ubuntu-22.04-Distro"})
+	if err == nil {
+		t.Fatalf("expected create to fail")
+	}
+	if got := len(server.Requests()); got != 1 {
+		t.Fatalf("expected 1 request, got %d", got)
+	}
+}
+
+func TestDoesNotRetryNonIdempotentGatewayErrors(t *testing.T) {
+	for _, statusCode := range []int{http.StatusBadGateway, http.StatusGatewayTimeout, http.StatusServiceUnavailable} {
+		server := fakeapi.New()
+		apiClient := newTestClient(server)
+
+		server.FailNext(1, statusCode, "")
+		_, err := apiClient.NewNode(context.Background(), &models.Node{Name: "web-1", Plan: "C2.40GB", Image: "Ubuntu-22.04-Distro"})
+		if err == nil {
+			t.Fatalf("expected create to fail on %d", statusCode)
+		}
+		if got := len(server.Requests()); got != 1 {
+			t.Fatalf("expected 1 request on %d, got %d", statusCode, got)
+		}
+		server.Close()
+	}
+}
+
+func TestRetriesNonIdempotentWhenToldToComeBack(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := newTestClient(server)
+
+	server.FailNext(1, http.StatusServiceUnavailable, "0")
+	server.FailNext(1, http.StatusTooManyRequests, "0")
+	if _, err := apiClient.NewNode(context.Background(), &models.Node{Name: "web-1", Plan: "C2.40GB", Image: "Ubuntu-22.04-Distro"}); err != nil {
+		t.Fatalf("NewNode: %s", err)
+	}
+	if got := len(server.Requests()); got != 3 {
+		t.Fatalf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestCancelledContextStopsRetrying(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := newTestClient(server)
+	apiClient.Max_backoff = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	server.FailNext(1, http.StatusServiceUnavailable, "30")
+	_, err := apiClient.GetSecurityGroups(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestNodeResponseWithNullFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code": 200, "message": "Success", "errors": {}, "data": {
+			"id": 1001, "name": "web-1", "status": "Creating", "is_locked": null,
+			"public_ip_address": null, "vpc_id": null, "ssh_keys": null, "security_group_id": null,
+			"image": null, "reserve_ip": null, "backup": null, "is_ipv6_availed": null}}`))
+	}))
+	defer server.Close()
+	apiClient := client.NewClient(fakeapi.APIKey, fakeapi.AuthToken, server.URL, "Delhi")
+
+	node, err := apiClient.GetNode(context.Background(), "1001")
+	if err != nil {
+		t.Fatalf("GetNode: %s", err)
+	}
+	if node.Id != 1001 || node.Public_ip_address != "" || node.Is_locked || node.SSH_keys != nil {
+		t.Fatalf("unexpected node %+v", node)
+	}
+	// Null and missing create time arguments must be told apart from false
+	// and "" so Read can keep the configured value.
+	if node.Image != nil || node.Reserve_ip != nil || node.Backup != nil || node.Is_ipv6_availed != nil ||
+		node.Region != nil || node.Default_public_ip != nil || node.Disable_password != nil {
+		t.Fatalf("expected null and missing fields to decode to nil, got %+v", node)
+	}
+}
+
+func TestUnexpectedNodeResponseIsAnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code": 200, "message": "Success", "errors": {}, "data": {"id": "1001", "is_locked": "no"}}`))
+	}))
+	defer server.Close()
+	apiClient := client.NewClient(fakeapi.APIKey, fakeapi.AuthToken, server.URL, "Delhi")
+
+	_, err := apiClient.GetNode(context.Background(), "1001")
+	if err == nil || !strings.Contains(err.Error(), "error decoding the response of GET nodes/1001/") {
+		t.Fatalf("expected a decoding error, got %v", err)
+	}
+}
+
+func TestNewNodeRequestBody(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding the request body: %s", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code": 200, "message": "Success", "errors": {}, "data": {"id": 1001, "status": "Creating"}}`))
+	}))
+	defer server.Close()
+	apiClient := client.NewClient(fakeapi.APIKey, fakeapi.AuthToken, server.URL, "Delhi")
+
+	if _, err := apiClient.NewNode(context.Background(), &models.Node{Name: "web-1", Default_public_ip: true}); err != nil {
+		t.Fatalf("NewNode: %s", err)
+	}
+	if got := body["default_public_ip"]; got != true {
+		t.Fatalf("expected default_public_ip true in the request, got %v", got)
+	}
+}
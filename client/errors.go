@@ -0,0 +1,105 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned by every Client method when the MyAccount API answers
+// with a non 2xx status code.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+
+	// Fields of the E2E response envelope, when the body could be decoded.
+	Code    int
+	Message string
+	Errors  interface{}
+
+	Body string
+}
+
+func (e *APIError) Error() string {
+	detail := e.Message
+	switch errs := e.Errors.(type) {
+	case nil:
+	case string:
+		if errs != "" && errs != e.Message {
+			detail = strings.TrimSpace(detail + " " + errs)
+		}
+	case map[string]interface{}:
+		if len(errs) > 0 {
+			detail = strings.TrimSpace(fmt.Sprintf("%s %v", detail, errs))
+		}
+	default:
+		detail = strings.TrimSpace(fmt.Sprintf("%s %v", detail, errs))
+	}
+	if detail == "" {
+		detail = e.Body
+	}
+	return fmt.Sprintf("%s %s: got a non 200 status code: %d - %s", e.Method, e.URL, e.StatusCode, detail)
+}
+
+type errorEnvelope struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Errors  interface{} `json:"errors"`
+}
+
+// newAPIError builds an APIError from a failed response. The apikey query
+// parameter is stripped from the recorded URL so it never ends up in logs or
+// diagnostics.
+func newAPIError(response *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: response.StatusCode,
+	}
+	if response.Request != nil {
+		apiErr.Method = response.Request.Method
+		u := *response.Request.URL
+		u.RawQuery = ""
+		apiErr.URL = u.String()
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return apiErr
+	}
+	apiErr.Body = string(body)
+
+	envelope := errorEnvelope{}
+	if err := json.Unmarshal(body, &envelope); err == nil {
+		apiErr.Code = envelope.Code
+		apiErr.Message = envelope.Message
+		apiErr.Errors = envelope.Errors
+	}
+	return apiErr
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == statusCode || apiErr.Code == statusCode
+}
+
+// IsNotFound reports whether err is an APIError for a missing object.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an APIError caused by the object being in
+// a state that does not allow the request.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsRateLimited reports whether err is an APIError caused by rate limiting.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxRetries = 4
+	DefaultMaxBackoff = 30 * time.Second
+
+	minBackoff = 1 * time.Second
+	userAgent  = "terraform-e2e"
+)
+
+// doRequest is the single path every Client method goes through. It adds the
+// apikey and location query parameters and auth headers, retries transient failures and
+// decodes a successful JSON response into out (when out is not nil). The
+// request and any wait between retries are bound to ctx.
+func (c *Client) doRequest(ctx context.Context, method string, path string, params url.Values, body interface{}, out interface{}) error {
+
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("apikey", c.Api_key)
+	if c.Location != "" {
+		params.Set("location", c.Location)
+	}
+	requestUrl := c.Api_endpoint + path
+
+	for attempt := 0; ; attempt++ {
+		var reqBody io.Reader
+		if payload != nil {
+			reqBody = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, requestUrl, reqBody)
+		if err != nil {
+			return err
+		}
+		req.URL.RawQuery = params.Encode()
+		req.Header.Add("Authorization", "Bearer "+c.Auth_token)
+		req.Header.Add("Content-Type", "application/json")
+		req.Header.Add("User-Agent", userAgent)
+
+		log.Printf("[DEBUG] %s %s (attempt %d)", method, requestUrl, attempt+1)
+		response, err := c.HttpClient.Do(req)
+		if err != nil {
+			if ctx.Err() == nil && attempt < c.Max_retries && isIdempotent(method) {
+				wait := c.backoff(attempt, nil)
+				log.Printf("[WARN] %s %s failed: %s, retrying in %s", method, requestUrl, err, wait)
+				if err := sleepContext(ctx, wait); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+
+		if response.StatusCode < 200 || response.StatusCode > 299 {
+			if attempt < c.Max_retries && isRetryableStatus(method, response.StatusCode, response) {
+				wait := c.backoff(attempt, response)
+				io.Copy(ioutil.Discard, response.Body)
+				response.Body.Close()
+				log.Printf("[WARN] %s %s returned %d, retrying in %s", method, requestUrl, response.StatusCode, wait)
+				if err := sleepContext(ctx, wait); err != nil {
+					return err
+				}
+				continue
+			}
+			apiErr := newAPIError(response)
+			response.Body.Close()
+			return apiErr
+		}
+
+		defer response.Body.Close()
+		if out == nil {
+			return nil
+		}
+		resBody, err := ioutil.ReadAll(response.Body)
+		if err != nil {
+			return err
+		}
+		// A response that does not match the typed models (a field changing
+		// type for example) is reported as an error instead of being
+		// half decoded.
+		if err := json.Unmarshal(resBody, out); err != nil {
+			return fmt.Errorf("error decoding the response of %s %s: %s", method, path, err)
+		}
+		return nil
+	}
+}
+
+func sleepContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// isRetryableStatus reports whether a failed response is worth retrying.
+// Idempotent methods are retried on rate limiting and any server error. Other
+// methods are only retried when the API is known to have rejected the request
+// without handling it: rate limiting, or a 503 telling when to come back. A
+// 502 or 504 may come after the API already acted on the request, and
+// retrying a create then launches the node twice.
+func isRetryableStatus(method string, statusCode int, response *http.Response) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if isIdempotent(method) {
+		return statusCode >= 500
+	}
+	return statusCode == http.StatusServiceUnavailable && response.Header.Get("Retry-After") != ""
+}
+
+// backoff returns how long to wait before the next attempt: the Retry-After
+// header when the server sent one, otherwise exponential backoff with jitter.
+// Both are capped at Max_backoff.
+func (c *Client) backoff(attempt int, response *http.Response) time.Duration {
+	maxBackoff := c.Max_backoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+
+	if response != nil {
+		if wait, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+			if wait > maxBackoff {
+				return maxBackoff
+			}
+			return wait
+		}
+	}
+
+	wait := minBackoff << uint(attempt)
+	if wait <= 0 || wait > maxBackoff {
+		wait = maxBackoff
+	}
+	// Jittering within the upper half of the window keeps concurrent resources
+	// from retrying in lockstep.
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
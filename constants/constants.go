@@ -1 +1,5 @@
 package constants
+
+// Locations are the E2E locations the MyAccount API serves. Each one is a
+// separate context, so an object created in one is not visible in another.
+var Locations = []string{"Delhi", "Mumbai"}
@@ -0,0 +1,124 @@
+package image
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func DataSourceImage() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"name", "image_id"},
+				Description:  "name of the saved image to look up",
+			},
+			"image_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "id of the saved image to look up",
+			},
+			"most_recent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When several images have the name, use the most recently created one instead of failing",
+			},
+			"template_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Value to use as saved_image_template_id of e2e_node",
+			},
+			"image_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"os_distribution": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"distro": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"sku_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"image_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"image_size": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creation_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		ReadContext: dataSourceReadImage,
+	}
+}
+
+func dataSourceReadImage(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client)
+	var diags diag.Diagnostics
+
+	log.Printf("[INFO] Inside image data source ")
+	Response, err := apiClient.GetSavedImages(ctx)
+	if err != nil {
+		return diag.Errorf("error finding saved images: %s", err)
+	}
+
+	name := d.Get("name").(string)
+	imageId := d.Get("image_id").(string)
+	images := []models.Image{}
+	for _, image := range Response.Data {
+		if (imageId != "" && image.Image_id == imageId) || (imageId == "" && image.Name == name) {
+			images = append(images, image)
+		}
+	}
+
+	var image models.Image
+	switch {
+	case len(images) == 0 && imageId != "":
+		return diag.Errorf("no saved image with id %s found", imageId)
+	case len(images) == 0:
+		return diag.Errorf("no saved image named %s found", name)
+	case len(images) == 1 || d.Get("most_recent").(bool):
+		image = mostRecentImage(images)
+	default:
+		ids := make([]string, 0, len(images))
+		for _, match := range images {
+			ids = append(ids, match.Image_id)
+		}
+		return diag.Errorf("%d saved images named %s found (ids %s), set most_recent or image_id to pick one", len(images), name, strings.Join(ids, ", "))
+	}
+
+	d.SetId(image.Image_id)
+	d.Set("name", image.Name)
+	d.Set("image_id", image.Image_id)
+	d.Set("template_id", int(image.Template_id))
+	d.Set("image_type", image.Image_type)
+	d.Set("os_distribution", image.Os_distribution)
+	d.Set("distro", image.Distro)
+	d.Set("sku_type", image.Sku_type)
+	d.Set("image_state", image.Image_state)
+	d.Set("image_size", image.Image_size)
+	d.Set("creation_time", image.Creation_time)
+
+	return diags
+}
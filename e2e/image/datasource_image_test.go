@@ -0,0 +1,57 @@
+package image_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceImage_lookup(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	server.AddImage(models.Image{
+		Template_id:     9002,
+		Image_type:      "private",
+		Os_distribution: "Ubuntu",
+		Name:            "base-image",
+		Image_id:        "9002",
+		Distro:          "Ubuntu-22.04",
+		Image_state:     "Ready",
+		Creation_time:   "2023-03-04T10:00:00Z",
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_image" "by_id" {
+  image_id = "9001"
+}
+
+data "e2e_image" "latest" {
+  name        = "base-image"
+  most_recent = true
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_image.by_id", "name", "base-image"),
+					resource.TestCheckResourceAttr("data.e2e_image.by_id", "template_id", "9001"),
+					resource.TestCheckResourceAttr("data.e2e_image.latest", "template_id", "9002"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_image" "ambiguous" {
+  name = "base-image"
+}
+`,
+				ExpectError: regexp.MustCompile(`2 saved images named base-image found`),
+			},
+		},
+	})
+}
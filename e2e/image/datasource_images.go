@@ -2,29 +2,53 @@ package image
 
 import (
 	"context"
-	// "fmt"
+	"fmt"
 	"log"
-	// "math"
-	// "regexp"
-
-	// "strconv"
-	//"strings"
+	"regexp"
+	"sort"
+	"time"
 
 	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
 
-	// "github.com/hashicorp/terraform-plugin-log"
-	// "github.com/hashicorp/terraform-plugin-log/tflog"
-
 	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func DataSourceImages() *schema.Resource {
 	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"name_regex"},
+				Description:   "Only images with this name are returned",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only images whose name matches this regular expression are returned",
+			},
+			"os_distribution": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only images of this distribution are returned",
+			},
+			"image_state": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only images in this state, for example Ready, are returned",
+			},
+			"most_recent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only return the most recently created of the matching images",
+			},
 			"image_list": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -82,16 +106,70 @@ func dataSourceReadImages(ctx context.Context, d *schema.ResourceData, m interfa
 
 	apiClient := m.(*client.Client)
 	log.Printf("[INFO] Inside images data source ")
-	Response, err := apiClient.GetSavedImages()
+	Response, err := apiClient.GetSavedImages(ctx)
 	if err != nil {
-		return diag.Errorf("error finding saved images")
+		return diag.Errorf("error finding saved images: %s", err)
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+	name := d.Get("name").(string)
+	osDistribution := d.Get("os_distribution").(string)
+	imageState := d.Get("image_state").(string)
+
+	images := []models.Image{}
+	for _, image := range Response.Data {
+		if name != "" && image.Name != name {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(image.Name) {
+			continue
+		}
+		if osDistribution != "" && image.Os_distribution != osDistribution {
+			continue
+		}
+		if imageState != "" && image.Image_state != imageState {
+			continue
+		}
+		images = append(images, image)
+	}
+	if d.Get("most_recent").(bool) && len(images) > 0 {
+		images = []models.Image{mostRecentImage(images)}
 	}
 
-	d.Set("image_list", flattenImages(&Response.Data))
-	d.SetId("saved_image_list")
+	d.Set("image_list", flattenImages(&images))
+	d.SetId(fmt.Sprintf("saved_image_list-%s", apiClient.Location))
 	var diags diag.Diagnostics
 	return diags
 }
+
+var imageTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "02-01-2006 15:04:05", "02/Jan/2006 03:04 PM"}
+
+func imageCreatedAt(image models.Image) time.Time {
+	for _, layout := range imageTimeLayouts {
+		if t, err := time.Parse(layout, image.Creation_time); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// mostRecentImage returns the image created last. Template ids only grow, so
+// they break ties and cover creation times that can not be parsed.
+func mostRecentImage(images []models.Image) models.Image {
+	sorted := append([]models.Image{}, images...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ti, tj := imageCreatedAt(sorted[i]), imageCreatedAt(sorted[j])
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return sorted[i].Template_id > sorted[j].Template_id
+	})
+	return sorted[0]
+}
+
 func flattenImages(imageList *[]models.Image) []interface{} {
 
 	if imageList != nil {
@@ -0,0 +1,66 @@
+package image_test
+
+import (
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceImages_basic(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_images" "test" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_images.test", "image_list.#", "1"),
+					resource.TestCheckResourceAttr("data.e2e_images.test", "image_list.0.name", "base-image"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceImages_filter(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	server.AddImage(models.Image{Template_id: 9002, Name: "base-image", Image_id: "9002", Os_distribution: "Ubuntu", Image_state: "Ready", Creation_time: "2023-03-04T10:00:00Z"})
+	server.AddImage(models.Image{Template_id: 9003, Name: "centos-golden", Image_id: "9003", Os_distribution: "CentOS", Image_state: "Creating", Creation_time: "2023-04-05T10:00:00Z"})
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_images" "ready" {
+  image_state = "Ready"
+}
+
+data "e2e_images" "latest_base" {
+  name_regex  = "^base-"
+  most_recent = true
+}
+
+data "e2e_images" "centos" {
+  os_distribution = "CentOS"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_images.ready", "image_list.#", "2"),
+					resource.TestCheckResourceAttr("data.e2e_images.latest_base", "image_list.#", "1"),
+					resource.TestCheckResourceAttr("data.e2e_images.latest_base", "image_list.0.image_id", "9002"),
+					resource.TestCheckResourceAttr("data.e2e_images.centos", "image_list.#", "1"),
+					resource.TestCheckResourceAttr("data.e2e_images.centos", "image_list.0.name", "centos-golden"),
+				),
+			},
+		},
+	})
+}
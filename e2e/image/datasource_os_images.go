@@ -0,0 +1,171 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func DataSourceOsImages() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"os": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only images of this distribution (for example Ubuntu) are returned. Case insensitive",
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only images of this version (for example 22.04) are returned",
+			},
+			"most_recent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only return the newest version of each distribution",
+			},
+			"image_list": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"os": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"image": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Value to use as the image of e2e_node",
+						},
+						"plans": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"locations": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+
+		ReadContext: dataSourceReadOsImages,
+	}
+}
+
+func dataSourceReadOsImages(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client)
+	var diags diag.Diagnostics
+
+	log.Printf("[INFO] Inside os images data source ")
+	images, err := apiClient.GetOsImages(ctx)
+	if err != nil {
+		return diag.Errorf("error listing os images: %s", err)
+	}
+
+	os := d.Get("os").(string)
+	version := d.Get("version").(string)
+	matched := []models.OsImage{}
+	for _, image := range images {
+		if os != "" && !strings.EqualFold(image.Os, os) {
+			continue
+		}
+		if version != "" && image.Version != version {
+			continue
+		}
+		matched = append(matched, image)
+	}
+	if d.Get("most_recent").(bool) {
+		matched = mostRecentOsImages(matched)
+	}
+
+	if err := d.Set("image_list", flattenOsImages(matched)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("os_image_list-%s-%s-%t", os, version, d.Get("most_recent").(bool)))
+
+	return diags
+}
+
+// mostRecentOsImages keeps the newest version of each distribution, in the
+// order the distributions were first listed.
+func mostRecentOsImages(images []models.OsImage) []models.OsImage {
+	newest := map[string]int{}
+	order := []string{}
+	for i, image := range images {
+		os := strings.ToLower(image.Os)
+		j, ok := newest[os]
+		if !ok {
+			order = append(order, os)
+			newest[os] = i
+			continue
+		}
+		if compareVersions(image.Version, images[j].Version) > 0 {
+			newest[os] = i
+		}
+	}
+
+	latest := make([]models.OsImage, 0, len(order))
+	for _, os := range order {
+		latest = append(latest, images[newest[os]])
+	}
+	return latest
+}
+
+// compareVersions compares dotted versions such as 20.04 and 22.04 part by
+// part, numerically when both parts are numbers.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil && xn != yn:
+			if xn < yn {
+				return -1
+			}
+			return 1
+		case (xerr != nil || yerr != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+func flattenOsImages(images []models.OsImage) []interface{} {
+	ois := make([]interface{}, 0, len(images))
+	for _, image := range images {
+		ois = append(ois, map[string]interface{}{
+			"os":        image.Os,
+			"version":   image.Version,
+			"image":     image.Image,
+			"plans":     image.Plans,
+			"locations": image.Locations,
+		})
+	}
+	return ois
+}
@@ -0,0 +1,43 @@
+package image_test
+
+import (
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceOsImages_filter(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_os_images" "all" {}
+
+data "e2e_os_images" "ubuntu" {
+  os          = "ubuntu"
+  most_recent = true
+}
+
+data "e2e_os_images" "focal" {
+  os      = "Ubuntu"
+  version = "20.04"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_os_images.all", "image_list.#", "3"),
+					resource.TestCheckResourceAttr("data.e2e_os_images.ubuntu", "image_list.#", "1"),
+					resource.TestCheckResourceAttr("data.e2e_os_images.ubuntu", "image_list.0.image", "Ubuntu-22.04-Distro"),
+					resource.TestCheckResourceAttr("data.e2e_os_images.ubuntu", "image_list.0.plans.#", "4"),
+					resource.TestCheckResourceAttr("data.e2e_os_images.focal", "image_list.#", "1"),
+					resource.TestCheckResourceAttr("data.e2e_os_images.focal", "image_list.0.image", "Ubuntu-20.04-Distro"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,239 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/e2e/node"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func ResourceImage() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"node_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "id of the node to save. A running node is powered off while the image is taken and started again afterwards",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "name of the saved image, must be unique in the image list",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location of the node. Defaults to the provider location",
+			},
+			"template_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Value to use as saved_image_template_id of e2e_node",
+			},
+			"image_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"image_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"os_distribution": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"distro": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"sku_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"image_size": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creation_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		CreateContext: resourceCreateImage,
+		ReadContext:   resourceReadImage,
+		DeleteContext: resourceDeleteImage,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+func findSavedImage(ctx context.Context, apiClient *client.Client, match func(image models.Image) bool) (*models.Image, error) {
+	Response, err := apiClient.GetSavedImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range Response.Data {
+		if match(image) {
+			return &image, nil
+		}
+	}
+	return nil, nil
+}
+
+func resourceCreateImage(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	d.Set("location", apiClient.Location)
+
+	log.Printf("[INFO] inside image create")
+	nodeId := d.Get("node_id").(string)
+	name := d.Get("name").(string)
+	timeout := d.Timeout(schema.TimeoutCreate)
+
+	existing, err := findSavedImage(ctx, apiClient, func(image models.Image) bool { return image.Name == name })
+	if err != nil {
+		return diag.Errorf("error finding saved images: %s", err)
+	}
+	if existing != nil {
+		return diag.Errorf("a saved image named %s already exists (id %s)", name, existing.Image_id)
+	}
+
+	resnode, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		return diag.Errorf("error finding node with ID %s: %s", nodeId, err)
+	}
+	status, nodeName := resnode.Status, resnode.Name
+	if resnode.Is_locked {
+		return diag.Errorf("cannot save an image of node %s as it is locked", nodeId)
+	}
+	if status != "Running" && status != "Powered off" {
+		return diag.Errorf("cannot save an image of node %s as it is in %s state", nodeId, status)
+	}
+
+	// The API only saves powered off nodes.
+	wasRunning := status == "Running"
+	if wasRunning {
+		if _, err := apiClient.UpdateNode(ctx, nodeId, "power_off", nodeName); err != nil {
+			return diag.Errorf("error powering off node %s before saving an image: %s", nodeId, err)
+		}
+		if err := node.WaitForNodeTransition(ctx, apiClient, nodeId, "Running", "Powered off", timeout); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	_, saveErr := apiClient.UpdateNode(ctx, nodeId, "save_images", name)
+	if saveErr == nil {
+		var image *models.Image
+		image, saveErr = waitForImageReady(ctx, apiClient, name, timeout)
+		if image != nil {
+			d.SetId(image.Image_id)
+		}
+	}
+
+	if wasRunning {
+		if _, err := apiClient.UpdateNode(ctx, nodeId, "power_on", nodeName); err != nil {
+			return diag.Errorf("error powering node %s back on after saving an image: %s", nodeId, err)
+		}
+		if err := node.WaitForNodeTransition(ctx, apiClient, nodeId, "Powered off", "Running", timeout); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if saveErr != nil {
+		return diag.Errorf("error saving image %s of node %s: %s", name, nodeId, saveErr)
+	}
+
+	return resourceReadImage(ctx, d, m)
+}
+
+// waitForImageReady waits for the image saved under name to be listed as
+// Ready. The image is returned as soon as it is listed, so that it can be
+// tracked even when it never becomes ready.
+func waitForImageReady(ctx context.Context, apiClient *client.Client, name string, timeout time.Duration) (*models.Image, error) {
+	var saved *models.Image
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"", "Creating", "Saving"},
+		Target:  []string{"Ready"},
+		Refresh: func() (interface{}, string, error) {
+			image, err := findSavedImage(ctx, apiClient, func(image models.Image) bool { return image.Name == name })
+			if err != nil {
+				return nil, "", err
+			}
+			if image == nil {
+				log.Printf("[INFO] saved image %s not listed yet", name)
+				return name, "", nil
+			}
+			saved = image
+			log.Printf("[INFO] saved image %s state %s", name, image.Image_state)
+			return image, image.Image_state, nil
+		},
+		Timeout:    timeout,
+		Delay:      10 * time.Second,
+		MinTimeout: 5 * time.Second,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		if timeoutErr, ok := err.(*resource.TimeoutError); ok {
+			return saved, fmt.Errorf("timed out after %s waiting for image %s to become Ready, last state: %s", timeout, name, timeoutErr.LastState)
+		}
+		return saved, fmt.Errorf("error waiting for image %s to become Ready: %s", name, err)
+	}
+	return saved, nil
+}
+
+func resourceReadImage(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+	d.Set("location", apiClient.Location)
+
+	image, err := findSavedImage(ctx, apiClient, func(image models.Image) bool { return image.Image_id == d.Id() })
+	if err != nil {
+		return diag.Errorf("error finding saved image with ID %s: %s", d.Id(), err)
+	}
+	if image == nil {
+		log.Printf("[WARN] saved image %s not found, removing from state", d.Id())
+		d.SetId("")
+		return diags
+	}
+
+	d.Set("name", image.Name)
+	d.Set("template_id", int(image.Template_id))
+	d.Set("image_id", image.Image_id)
+	d.Set("image_state", image.Image_state)
+	d.Set("os_distribution", image.Os_distribution)
+	d.Set("distro", image.Distro)
+	d.Set("sku_type", image.Sku_type)
+	d.Set("image_size", image.Image_size)
+	d.Set("creation_time", image.Creation_time)
+
+	return diags
+}
+
+func resourceDeleteImage(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+
+	err := apiClient.DeleteSavedImage(ctx, d.Id())
+	if err != nil && !client.IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return diags
+}
@@ -0,0 +1,76 @@
+package image_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccImage_basic(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckImageDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_node" "source" {
+  name  = "image-source"
+  label = "acc"
+  plan  = "C2.40GB"
+  image = "Ubuntu-22.04-Distro"
+}
+
+resource "e2e_image" "test" {
+  node_id = e2e_node.source.id
+  name    = "acc-golden"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_image.test", "image_state", "Ready"),
+					resource.TestCheckResourceAttr("e2e_image.test", "distro", "Ubuntu-22.04-Distro"),
+					resource.TestCheckResourceAttrSet("e2e_image.test", "template_id"),
+					testAccCheckNodeStatus(server, "e2e_node.source", "Running"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckNodeStatus(server *fakeapi.Server, name string, status string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		id, err := strconv.Atoi(s.RootModule().Resources[name].Primary.ID)
+		if err != nil {
+			return err
+		}
+		node, ok := server.Node(id)
+		if !ok {
+			return fmt.Errorf("node %d not found", id)
+		}
+		if node.Status != status {
+			return fmt.Errorf("expected node %d to be %s, got %s", id, status, node.Status)
+		}
+		return nil
+	}
+}
+
+func testAccCheckImageDestroy(server *fakeapi.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "e2e_image" {
+				continue
+			}
+			if image, ok := server.Image(rs.Primary.ID); ok {
+				return fmt.Errorf("saved image %s (%s) still exists", image.Name, rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
@@ -2,133 +2,96 @@ package node
 
 import (
 	"context"
-	// "fmt"
+	"fmt"
 	"log"
-	// "math"
-	// "regexp"
-
-	// "strconv"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
-	// "github.com/devteametwoe/terraform-provider-e2e/models"
-
-	// "github.com/hashicorp/terraform-plugin-log"
-	// "github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func DataSourceNode() *schema.Resource {
 	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 
-			"name": {
+			"node_id": {
 				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "The name of the resource, also acts as it's unique ID",
-				ForceNew:     true,
-				ValidateFunc: validateName,
-			},
-			"label": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the group",
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"node_id", "name"},
+				ValidateFunc: validation.StringMatch(nodeIdPattern, "expected a numeric node id"),
+				Description:  "id of the node to look up",
 			},
-			"plan": {
+			"name": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the Plan",
+				Optional:    true,
+				Computed:    true,
+				Description: "name of the node to look up. Exactly one node must have this name",
 			},
-			"os": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "OS and its version  format : <OS>-<version>",
+			"label": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"node_id"},
+				Description:   "narrows a lookup by name to the nodes with this label",
 			},
-			"backup": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Description: "Tells you the state of your backups",
-				Default:     false,
+			"plan": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 			"image": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The name of the image you have selected",
-				Default:     "CentOS-7.5-Distro",
+				Type:     schema.TypeString,
+				Computed: true,
 			},
-			"default_public_ip": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Description: "Tells us the state of default public ip",
-				Default:     false,
+			"region": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
-			"disable_password": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Description: "",
-				Default:     false,
+			"vpc_id": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
-			"enable_bitninja": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Description: "",
-				Default:     false,
+			"vpc_private_ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "private IPs of the node in each vpc it is attached to",
 			},
-			"is_ipv6_availed": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Description: "",
-				Default:     false,
+			"security_group_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
 			},
-			"is_saved_image": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Description: "",
-				Default:     false,
+			"security_group_ids": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "ids of all the security groups attached to the node",
 			},
-			"region": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "",
-				Default:     "ncr",
+			"ssh_keys": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 			"reserve_ip": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "",
-				Default:     "",
-			},
-			"vpc_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "",
-				Default:     "",
-			},
-			"ngc_container_id": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "",
-				Default:     nil,
+				Type:     schema.TypeString,
+				Computed: true,
 			},
-			"saved_image_template_id": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "",
-				Default:     nil,
+			"is_ipv6_availed": {
+				Type:     schema.TypeBool,
+				Computed: true,
 			},
-			"security_group_id": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "",
-				Default:     150,
+			"default_public_ip": {
+				Type:     schema.TypeBool,
+				Computed: true,
 			},
-			"ssh_keys": {
-				Type:        schema.TypeSet,
-				Optional:    true,
-				Description: "",
-				Elem:        &schema.Schema{Type: schema.TypeString},
+			"backup": {
+				Type:     schema.TypeBool,
+				Computed: true,
 			},
 			"is_active": {
 				Type:     schema.TypeBool,
@@ -156,8 +119,7 @@ func DataSourceNode() *schema.Resource {
 			},
 			"power_status": {
 				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "power_on",
+				Computed: true,
 			},
 			"public_ip_address": {
 				Type:     schema.TypeString,
@@ -175,70 +137,106 @@ func DataSourceNode() *schema.Resource {
 				Type:     schema.TypeBool,
 				Computed: true,
 			},
-			"lock_node": {
+			"is_locked": {
 				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  "false",
-			},
-			"reboot_node": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  "false",
-			},
-			"reinstall_node": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  "false",
+				Computed: true,
 			},
 		},
 
 		ReadContext: dataSourceReadNode,
-
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
 	}
 }
+
+var nodeIdPattern = regexp.MustCompile(`^[0-9]+$`)
+
 func dataSourceReadNode(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
 	apiClient := m.(*client.Client)
 	var diags diag.Diagnostics
 	log.Printf("[INFO] inside node data source read")
-	nodeId := d.Id()
 
-	node, err := apiClient.GetNode(nodeId)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			d.SetId("")
-		} else {
-			return diag.Errorf("error finding Item with ID %s", nodeId)
+	nodeId := d.Get("node_id").(string)
+	if nodeId == "" {
+		id, err := findNodeIdByName(ctx, apiClient, d.Get("name").(string), d.Get("label").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		nodeId = id
+	}
 
+	node, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		if client.IsNotFound(err) {
+			return diag.Errorf("node with ID %s not found", nodeId)
 		}
+		return diag.Errorf("error finding node with ID %s: %s", nodeId, err)
 	}
-	data := node["data"].(map[string]interface{})
-
-	d.Set("name", data["name"].(string))
-	d.Set("label", data["label"].(string))
-	d.Set("plan", data["plan"].(string))
-	d.Set("backup", data["backup"].(bool))
-	d.Set("is_active", data["is_active"].(bool))
-	d.Set("created_at", data["created_at"].(string))
-	d.Set("memory", data["memory"].(string))
-	d.Set("status", data["status"].(string))
-	d.Set("disk", data["disk"].(string))
-	d.Set("price", data["price"].(string))
-	d.Set("lock_node", data["is_locked"].(bool))
-	d.Set("public_ip_address", data["public_ip_address"].(string))
-	d.Set("private_ip_address", data["private_ip_address"].(string))
-	d.Set("is_monitored", data["is_monitored"].(bool))
-	d.Set("is_bitninja_license_active", data["is_bitninja_license_active"].(bool))
-	if d.Get("status").(string) == "Running" {
-		d.Set("power_status", "power_on")
+
+	d.SetId(nodeId)
+	d.Set("node_id", nodeId)
+	d.Set("name", node.Name)
+	d.Set("label", node.Label)
+	d.Set("plan", node.Plan)
+	d.Set("image", node.Image)
+	d.Set("region", node.Region)
+	d.Set("vpc_id", node.Vpc_id)
+	vpcPrivateIps := []string{}
+	for _, vpc := range node.Vpcs {
+		vpcPrivateIps = append(vpcPrivateIps, vpc.Private_ip)
 	}
-	if d.Get("status").(string) == "Powered off" {
+	d.Set("vpc_private_ips", vpcPrivateIps)
+	d.Set("security_group_id", node.Security_group_id)
+	if err := d.Set("security_group_ids", attachedSecurityGroupIds(node)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("ssh_keys", node.SSH_keys); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("reserve_ip", node.Reserve_ip)
+	d.Set("is_ipv6_availed", node.Is_ipv6_availed)
+	d.Set("default_public_ip", node.Default_public_ip)
+	d.Set("backup", node.Backup)
+	d.Set("is_active", node.Is_active)
+	d.Set("created_at", node.Created_at)
+	d.Set("memory", node.Memory)
+	d.Set("status", node.Status)
+	d.Set("disk", node.Disk)
+	d.Set("price", node.Price)
+	d.Set("public_ip_address", node.Public_ip_address)
+	d.Set("private_ip_address", node.Private_ip_address)
+	d.Set("is_monitored", node.Is_monitored)
+	d.Set("is_bitninja_license_active", node.Is_bitninja_license_active)
+	d.Set("is_locked", node.Is_locked)
+	switch node.Status {
+	case "Running":
+		d.Set("power_status", "power_on")
+	case "Powered off":
 		d.Set("power_status", "power_off")
 	}
 
 	return diags
+}
 
+func findNodeIdByName(ctx context.Context, apiClient *client.Client, name string, label string) (string, error) {
+	nodes, err := apiClient.ListNodes(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error listing nodes: %s", err)
+	}
+
+	ids := []string{}
+	for _, node := range nodes {
+		if node.Name != name || (label != "" && node.Label != label) {
+			continue
+		}
+		ids = append(ids, strconv.Itoa(node.Id))
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("no node named %s found in %s", name, apiClient.Location)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%d nodes named %s found in %s (ids %s), set label or node_id to pick one", len(ids), name, apiClient.Location, strings.Join(ids, ", "))
+	}
 }
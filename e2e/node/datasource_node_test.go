@@ -0,0 +1,82 @@
+package node_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceNode_lookup(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccDataSourceNodeConfig,
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccDataSourceNodeConfig + `
+data "e2e_node" "by_id" {
+  node_id = e2e_node.blue.id
+}
+
+data "e2e_node" "by_name" {
+  name  = "app"
+  label = "green"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_node.by_id", "label", "blue"),
+					resource.TestCheckResourceAttr("data.e2e_node.by_id", "status", "Running"),
+					resource.TestCheckResourceAttr("data.e2e_node.by_id", "power_status", "power_on"),
+					resource.TestCheckResourceAttrPair("data.e2e_node.by_id", "public_ip_address", "e2e_node.blue", "public_ip_address"),
+					resource.TestCheckResourceAttr("data.e2e_node.by_id", "image", "Ubuntu-22.04-Distro"),
+					resource.TestCheckResourceAttr("data.e2e_node.by_id", "security_group_id", "150"),
+					resource.TestCheckResourceAttr("data.e2e_node.by_id", "security_group_ids.#", "1"),
+					resource.TestCheckResourceAttr("data.e2e_node.by_id", "is_ipv6_availed", "false"),
+					resource.TestCheckResourceAttrPair("data.e2e_node.by_id", "vpc_private_ips.0", "e2e_node.blue", "vpc_private_ips.0"),
+					resource.TestCheckResourceAttrPair("data.e2e_node.by_name", "node_id", "e2e_node.green", "id"),
+					resource.TestCheckResourceAttr("data.e2e_node.by_name", "plan", "C2.80GB"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccDataSourceNodeConfig + `
+data "e2e_node" "ambiguous" {
+  name = "app"
+}
+`,
+				ExpectError: regexp.MustCompile(`2 nodes named app found`),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccDataSourceNodeConfig + `
+data "e2e_node" "missing" {
+  name = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`no node named missing found`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceNodeConfig = `
+resource "e2e_node" "blue" {
+  name   = "app"
+  label  = "blue"
+  plan   = "C2.40GB"
+  image  = "Ubuntu-22.04-Distro"
+  vpc_id = "301"
+}
+
+resource "e2e_node" "green" {
+  name  = "app"
+  label = "green"
+  plan  = "C2.80GB"
+  image = "Ubuntu-22.04-Distro"
+}
+`
@@ -1 +1,207 @@
 package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// nodeFilterFields maps the names accepted in a filter block to the node
+// attribute they match against.
+var nodeFilterFields = map[string]func(node models.NodeDetail) string{
+	"name":   func(node models.NodeDetail) string { return node.Name },
+	"label":  func(node models.NodeDetail) string { return node.Label },
+	"status": func(node models.NodeDetail) string { return node.Status },
+	"plan":   func(node models.NodeDetail) string { return node.Plan },
+	"region": func(node models.NodeDetail) string { return stringValue(node.Region) },
+	"vpc_id": func(node models.NodeDetail) string { return node.Vpc_id },
+}
+
+func DataSourceNodes() *schema.Resource {
+	filterNames := make([]string, 0, len(nodeFilterFields))
+	for name := range nodeFilterFields {
+		filterNames = append(filterNames, name)
+	}
+	sort.Strings(filterNames)
+
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only nodes whose name matches this regular expression are returned",
+			},
+			"filter": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Only nodes matching every filter block are returned. A filter matches when the field equals one of the values",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(filterNames, false),
+							Description:  "Field to filter on, one of name, label, status, plan, region or vpc_id",
+						},
+						"values": {
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"node_list": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"label": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"plan": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"region": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vpc_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"public_ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"private_ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"is_locked": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+
+		ReadContext: dataSourceReadNodes,
+	}
+}
+
+func dataSourceReadNodes(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client)
+	var diags diag.Diagnostics
+
+	log.Printf("[INFO] Inside nodes data source ")
+	nodes, err := apiClient.ListNodes(ctx)
+	if err != nil {
+		return diag.Errorf("error listing nodes: %s", err)
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+	filters := d.Get("filter").(*schema.Set).List()
+
+	matched := []models.NodeDetail{}
+	for _, node := range nodes {
+		if nameRegex != nil && !nameRegex.MatchString(node.Name) {
+			continue
+		}
+		if !nodeMatchesFilters(node, filters) {
+			continue
+		}
+		matched = append(matched, node)
+	}
+
+	ids := make([]string, 0, len(matched))
+	for _, node := range matched {
+		ids = append(ids, strconv.Itoa(node.Id))
+	}
+	d.Set("ids", ids)
+	if err := d.Set("node_list", flattenNodes(matched)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("node_list-%s", apiClient.Location))
+
+	return diags
+}
+
+func nodeMatchesFilters(node models.NodeDetail, filters []interface{}) bool {
+	for _, f := range filters {
+		filter := f.(map[string]interface{})
+		value := nodeFilterFields[filter["name"].(string)](node)
+		if !filter["values"].(*schema.Set).Contains(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func flattenNodes(nodes []models.NodeDetail) []interface{} {
+	ois := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		ois = append(ois, map[string]interface{}{
+			"id":                 strconv.Itoa(node.Id),
+			"name":               node.Name,
+			"label":              node.Label,
+			"plan":               node.Plan,
+			"status":             node.Status,
+			"region":             stringValue(node.Region),
+			"vpc_id":             node.Vpc_id,
+			"public_ip_address":  node.Public_ip_address,
+			"private_ip_address": node.Private_ip_address,
+			"is_locked":          node.Is_locked,
+			"created_at":         node.Created_at,
+		})
+	}
+	return ois
+}
+
+// stringValue returns the value of a string the API may report as null, ""
+// when it did.
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,66 @@
+package node_test
+
+import (
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceNodes_filter(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccDataSourceNodesConfig,
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccDataSourceNodesConfig + `
+data "e2e_nodes" "web" {
+  name_regex = "^web-"
+
+  filter {
+    name   = "status"
+    values = ["Running"]
+  }
+}
+
+data "e2e_nodes" "by_plan" {
+  filter {
+    name   = "plan"
+    values = ["C2.80GB"]
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_nodes.web", "node_list.#", "2"),
+					resource.TestCheckResourceAttr("data.e2e_nodes.web", "ids.#", "2"),
+					resource.TestCheckResourceAttr("data.e2e_nodes.by_plan", "node_list.#", "1"),
+					resource.TestCheckResourceAttr("data.e2e_nodes.by_plan", "node_list.0.name", "db-1"),
+					resource.TestCheckResourceAttrPair("data.e2e_nodes.by_plan", "ids.0", "e2e_node.db", "id"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceNodesConfig = `
+resource "e2e_node" "web" {
+  count = 2
+  name  = "web-${count.index}"
+  label = "web"
+  plan  = "C2.40GB"
+  image = "Ubuntu-22.04-Distro"
+}
+
+resource "e2e_node" "db" {
+  name  = "db-1"
+  label = "db"
+  plan  = "C2.80GB"
+  image = "Ubuntu-22.04-Distro"
+}
+`
@@ -5,21 +5,29 @@ import (
 
 	"fmt"
 	"log"
-	"math"
 	"regexp"
 
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
 	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
 
 	// "github.com/hashicorp/terraform-plugin-log"
 	// "github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func ResourceNode() *schema.Resource {
@@ -35,16 +43,18 @@ func ResourceNode() *schema.Resource {
 			},
 			"label": {
 				Type:        schema.TypeString,
+				ForceNew:    true,
 				Required:    true,
-				Description: "The name of the group",
+				Description: "The name of the group. The API cannot relabel a node, so changing it replaces the node",
 			},
 			"plan": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "name of the Plan",
+				Description: "name of the Plan. Changing it resizes the node in place, powering it off for the duration of the resize",
 			},
 			"backup": {
 				Type:        schema.TypeBool,
+				ForceNew:    true,
 				Optional:    true,
 				Description: "Tells you the state of your backups",
 				Default:     false,
@@ -53,7 +63,7 @@ func ResourceNode() *schema.Resource {
 			"image": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "The name of the image you have selected format :- ( os-version )",
+				Description: "The name of the image you have selected format :- ( os-version ). Like the other create time arguments (region, reserve_ip, ssh_keys, the saved image and NGC arguments and the flags other than backup) it cannot be changed on an existing node",
 			},
 			"default_public_ip": {
 				Type:        schema.TypeBool,
@@ -88,8 +98,16 @@ func ResourceNode() *schema.Resource {
 			"region": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Location where node is to be launched",
-				Default:     "Delhi",
+				Computed:    true,
+				Description: "Location where node is to be launched. Defaults to the location of the node",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location context the node is managed in. Defaults to the provider location",
 			},
 			"reserve_ip": {
 				Type:        schema.TypeString,
@@ -100,26 +118,41 @@ func ResourceNode() *schema.Resource {
 			"vpc_id": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Vpc id as per requirement",
-				Default:     "",
+				Description: "Vpc id as per requirement. Changing it moves the node to the new vpc in place, attaching it before detaching the old one, and removing it detaches the node from the vpc. Use e2e_vpc_attachment to attach the node to more vpcs",
+			},
+			"vpc_private_ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "private IPs of the node in each vpc it is attached to",
 			},
 			"ngc_container_id": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "",
-				Default:     nil,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "id of the NGC container to launch the node with",
 			},
 			"saved_image_template_id": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "template id  is required when you save the node from saved images.Give the template id of the saved image. Required when is_saved_image field is true",
-				Default:     nil,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "template id of the saved image to launch the node from, see the template_id of e2e_image. Required when is_saved_image is true and not allowed otherwise",
 			},
 			"security_group_id": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "Specify the security group. Checkout security_groups datasource listing security groups",
-				Default:     150,
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"security_group_ids"},
+				Description:   "Specify the security group. Checkout security_groups datasource listing security groups. Defaults to the default security group. Changing it attaches the new group and detaches the old one in place",
+			},
+			"security_group_ids": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Computed:      true,
+				MinItems:      1,
+				ConflictsWith: []string{"security_group_id"},
+				Elem:          &schema.Schema{Type: schema.TypeInt},
+				Description:   "ids of all the security groups of the node, use instead of security_group_id to give the node several groups. Groups are attached and detached in place. Leave unset when using e2e_node_security_group_attachment",
 			},
 			"ssh_keys": {
 				Type:        schema.TypeList,
@@ -127,6 +160,15 @@ func ResourceNode() *schema.Resource {
 				Description: "Specify the ssh keys if required. Checkout ssh_keys datasource for listing ssh keys",
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"user_data": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				StateFunc:        hashUserData,
+				DiffSuppressFunc: suppressImportedUserData,
+				ValidateFunc:     validation.StringLenBetween(0, maxUserDataSize),
+				Description:      "cloud-init user data or start script run when the node boots for the first time, for example the output of templatefile(). At most 16 KB. Only a hash of it is stored in the state. Changing it replaces the node, except on an imported node whose user_data is not known",
+			},
 			"is_active": {
 				Type:     schema.TypeBool,
 				Computed: true,
@@ -169,39 +211,17 @@ func ResourceNode() *schema.Resource {
 				Computed: true,
 			},
 			"power_status": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "power_on",
-				Description: "power_on to start the node and power_off to power off the node",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "power_on",
+				ValidateFunc: validation.StringInSlice([]string{"power_on", "power_off"}, false),
+				Description:  "power_on to start the node and power_off to power off the node",
 			},
 			"lock_node": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
-				Description: "Node is locked when set true .Can specify wheather to lock the node or not",
-			},
-			"reboot_node": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "When set true node will be rebooted. Node should be in running state to perform rebooting.Alaways check the field. If you have an active disk-intensive process such as database, backups running, then a rebooting may lead to data corruption and data loss (best option is to reboot the machine from within Operating System). ",
-			},
-			"reinstall_node": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "for reinstalling the node. Node should be in running state to perform this action. Always check this field as it will delete all your data permenantly when set true.",
-			},
-			"save_image": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "For saving image of the node. The node should be in power_off state to perform this action ",
-			},
-			"save_image_name": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Specify the name of the image to be saved. this field is required when save_image field is true. The name should be unique in the image list. Checkout images datasource to list them images",
+				Description: "Node is locked when set true .Can specify wheather to lock the node or not. Other changes are made before locking the node and after unlocking it",
 			},
 		},
 
@@ -209,9 +229,13 @@ func ResourceNode() *schema.Resource {
 		ReadContext:   resourceReadNode,
 		UpdateContext: resourceUpdateNode,
 		DeleteContext: resourceDeleteNode,
-		Exists:        resourceExistsNode,
+		CustomizeDiff: customizeNodeDiff,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceImportNode,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
 		},
 	}
 }
@@ -233,94 +257,533 @@ func validateName(v interface{}, k string) (ws []string, es []error) {
 	return warns, errs
 }
 
+// maxUserDataSize is the largest user_data, in bytes, the API accepts.
+const maxUserDataSize = 16 * 1024
+
+// hashUserData keeps user_data, which often holds secrets, out of the state.
+// The API does not return it, so the hash is only compared against the
+// configuration to detect changes.
+func hashUserData(v interface{}) string {
+	userData := v.(string)
+	if userData == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(userData))
+	return hex.EncodeToString(hash[:])
+}
+
+// importedUserData is stored as the user_data of an imported node, the API
+// does not return it. It cannot be mistaken for a hash.
+const importedUserData = "imported"
+
+// suppressImportedUserData keeps an imported node from being replaced because
+// of its user_data. A node created without user_data is still replaced when
+// it is added.
+func suppressImportedUserData(k, old, new string, d *schema.ResourceData) bool {
+	return old == importedUserData
+}
+
+// nodeCreateArgs are the arguments only taken when the node is created. The
+// API does not reliably report them back, so Read leaves them alone and a
+// change cannot be told from drift to replace the node.
+var nodeCreateArgs = []string{
+	"image",
+	"region",
+	"reserve_ip",
+	"ssh_keys",
+	"default_public_ip",
+	"disable_password",
+	"is_ipv6_availed",
+	"enable_bitninja",
+	"is_saved_image",
+	"saved_image_template_id",
+	"ngc_container_id",
+}
+
+// customizeNodeDiff makes sure saved_image_template_id is given exactly when
+// the node is launched from a saved image. It also marks the security group
+// argument that is not configured as changing along with the other one, and
+// rejects changes to the create time arguments of an existing node.
+func customizeNodeDiff(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+	if diff.Id() != "" {
+		rawState := diff.GetRawState()
+		for _, key := range nodeCreateArgs {
+			// A value missing from the state was never reported, for example
+			// by an import, take the configured one.
+			if !rawState.IsNull() && rawState.GetAttr(key).IsNull() {
+				continue
+			}
+			if diff.HasChange(key) {
+				return fmt.Errorf("%s cannot be changed on an existing node, replace the node with terraform apply -replace to change it", key)
+			}
+		}
+		if diff.HasChange("vpc_id") {
+			if err := diff.SetNewComputed("vpc_private_ips"); err != nil {
+				return err
+			}
+		}
+		if diff.HasChange("security_group_ids") {
+			if err := diff.SetNewComputed("security_group_id"); err != nil {
+				return err
+			}
+		} else if diff.HasChange("security_group_id") {
+			if err := diff.SetNewComputed("security_group_ids"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !diff.NewValueKnown("is_saved_image") || !diff.NewValueKnown("saved_image_template_id") {
+		return nil
+	}
+	isSavedImage := diff.Get("is_saved_image").(bool)
+	templateId := diff.Get("saved_image_template_id").(int)
+	if isSavedImage && templateId == 0 {
+		return fmt.Errorf("saved_image_template_id is required when is_saved_image is true")
+	}
+	if !isSavedImage && templateId != 0 {
+		return fmt.Errorf("saved_image_template_id can only be set when is_saved_image is true")
+	}
+	return nil
+}
+
+// checkSavedImageTemplate fails early, before a node is ordered, when the
+// template is not one of the saved images of the location.
+func checkSavedImageTemplate(ctx context.Context, apiClient *client.Client, templateId int) error {
+	Response, err := apiClient.GetSavedImages(ctx)
+	if err != nil {
+		return fmt.Errorf("error finding saved images: %s", err)
+	}
+	for _, image := range Response.Data {
+		if int(image.Template_id) != templateId {
+			continue
+		}
+		if image.Image_state != "Ready" {
+			return fmt.Errorf("saved image %s (template %d) is %s, it must be Ready to launch a node from it", image.Name, templateId, image.Image_state)
+		}
+		return nil
+	}
+	return fmt.Errorf("no saved image with template id %d found in %s, see the e2e_images data source for the available templates", templateId, apiClient.Location)
+}
+
 func resourceCreateNode(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*client.Client)
-	var diags diag.Diagnostics
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
 
 	log.Printf("[INFO] inside create ")
+	d.Set("location", apiClient.Location)
 	node := models.Node{
-		Name:              d.Get("name").(string),
-		Label:             d.Get("label").(string),
-		Plan:              d.Get("plan").(string),
-		Backup:            d.Get("backup").(bool),
-		Image:             d.Get("image").(string),
-		Default_public_ip: d.Get("default_public_ip").(bool),
-		Disable_password:  d.Get("disable_password").(bool),
-		Enable_bitninja:   d.Get("enable_bitninja").(bool),
-		Is_ipv6_availed:   d.Get("is_ipv6_availed").(bool),
-		Is_saved_image:    d.Get("is_saved_image").(bool),
-		Region:            d.Get("region").(string),
-		Reserve_ip:        d.Get("reserve_ip").(string),
-		Vpc_id:            d.Get("vpc_id").(string),
-		Security_group_id: d.Get("security_group_id").(int),
-		SSH_keys:          d.Get("ssh_keys").([]interface{}),
-	}
-
-	resnode, err := apiClient.NewNode(&node)
+		Name:                    d.Get("name").(string),
+		Label:                   d.Get("label").(string),
+		Plan:                    d.Get("plan").(string),
+		Backup:                  d.Get("backup").(bool),
+		Image:                   d.Get("image").(string),
+		Default_public_ip:       d.Get("default_public_ip").(bool),
+		Disable_password:        d.Get("disable_password").(bool),
+		Enable_bitninja:         d.Get("enable_bitninja").(bool),
+		Is_ipv6_availed:         d.Get("is_ipv6_availed").(bool),
+		Is_saved_image:          d.Get("is_saved_image").(bool),
+		Region:                  d.Get("region").(string),
+		Reserve_ip:              d.Get("reserve_ip").(string),
+		Vpc_id:                  d.Get("vpc_id").(string),
+		SSH_keys:                d.Get("ssh_keys").([]interface{}),
+		Ngc_container_id:        d.Get("ngc_container_id").(int),
+		Saved_image_template_id: d.Get("saved_image_template_id").(int),
+		User_data:               d.Get("user_data").(string),
+	}
+
+	if node.Region == "" {
+		node.Region = apiClient.Location
+		d.Set("region", node.Region)
+	}
+
+	securityGroupIds, err := nodeSecurityGroupIds(ctx, apiClient, d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	log.Printf("[INFO] resnode[code] %f", resnode["code"].(float64))
-	if resnode["code"].(float64) != 200 {
-		error := resnode["errors"].(string)
-		log.Printf(error)
-		return diag.Errorf(error)
+	node.Security_group_id = securityGroupIds[0]
+
+	if node.Is_saved_image {
+		if err := checkSavedImageTemplate(ctx, apiClient, node.Saved_image_template_id); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
+	resnode, err := apiClient.NewNode(ctx, &node)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] resnode code %d", resnode.Code)
+	if resnode.Code != http.StatusOK {
+		return diag.Errorf("error creating node %s: %d %s %v", node.Name, resnode.Code, resnode.Message, resnode.Errors)
+	}
+	if resnode.Data.Id == 0 {
+		return diag.Errorf("error creating node %s: the response did not include the node id", node.Name)
 	}
+	d.SetId(strconv.Itoa(resnode.Data.Id))
 
-	data := resnode["data"].(map[string]interface{})
-	nodeId := data["id"].(float64)
-	nodeId = math.Round(nodeId)
-	fmt.Println(data)
-	d.SetId(strconv.Itoa(int(math.Round(nodeId))))
-	d.Set("is_active", data["is_active"].(bool))
-	d.Set("created_at", data["created_at"].(string))
-	d.Set("memory", data["memory"].(string))
-	d.Set("status", data["status"].(string))
-	d.Set("disk", data["disk"].(string))
-	d.Set("price", data["price"].(string))
+	if err := WaitForNodeStatus(ctx, apiClient, d.Id(), "Running", d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
 
-	return diags
+	if len(securityGroupIds) > 1 {
+		if err := apiClient.AttachSecurityGroups(ctx, d.Id(), securityGroupIds[1:]); err != nil {
+			return diag.Errorf("error attaching security groups to node %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.Get("power_status").(string) == "power_off" {
+		if err := setNodePower(ctx, apiClient, d.Id(), "power_off", node.Name, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.Get("lock_node").(bool) {
+		if _, err := apiClient.UpdateNode(ctx, d.Id(), "lock_vm", ""); err != nil {
+			return diag.Errorf("error locking node %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceReadNode(ctx, d, m)
+}
+
+// nodeSecurityGroupIds returns the security groups to launch the node with.
+// When none is configured the default security group of the account is used.
+func nodeSecurityGroupIds(ctx context.Context, apiClient *client.Client, d *schema.ResourceData) ([]int, error) {
+	if v, ok := d.GetOk("security_group_ids"); ok {
+		return expandSecurityGroupIds(v.(*schema.Set)), nil
+	}
+	if v, ok := d.GetOk("security_group_id"); ok {
+		return []int{v.(int)}, nil
+	}
+
+	Response, err := apiClient.GetSecurityGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error finding the default security group: %s", err)
+	}
+	for _, group := range Response.Data {
+		if group.Is_default {
+			return []int{int(group.Id)}, nil
+		}
+	}
+	return nil, fmt.Errorf("no default security group found in %s, set security_group_id", apiClient.Location)
+}
+
+func expandSecurityGroupIds(set *schema.Set) []int {
+	ids := make([]int, 0, set.Len())
+	for _, v := range set.List() {
+		ids = append(ids, v.(int))
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// attachedSecurityGroupIds returns the ids of the groups attached to node.
+func attachedSecurityGroupIds(node *models.NodeDetail) []int {
+	ids := []int{}
+	for _, group := range node.Security_groups {
+		ids = append(ids, group.Id)
+	}
+	if len(ids) == 0 && node.Security_group_id != 0 {
+		ids = append(ids, node.Security_group_id)
+	}
+	return ids
+}
+
+// updateNodeSecurityGroups attaches the groups added to the configuration
+// before detaching the removed ones, so the node is never left without one.
+// attached are the groups currently attached to the node.
+func updateNodeSecurityGroups(ctx context.Context, apiClient *client.Client, d *schema.ResourceData, attached []int) error {
+	nodeId := d.Id()
+	var oldIds, newIds []int
+	// security_group_ids also changes, to unknown, when only security_group_id
+	// is configured. A configured set is never empty.
+	if o, n := d.GetChange("security_group_ids"); d.HasChange("security_group_ids") && n.(*schema.Set).Len() > 0 {
+		oldIds, newIds = expandSecurityGroupIds(o.(*schema.Set)), expandSecurityGroupIds(n.(*schema.Set))
+	} else {
+		o, n := d.GetChange("security_group_id")
+		oldIds, newIds = []int{o.(int)}, []int{n.(int)}
+	}
+
+	attach, detach := []int{}, []int{}
+	for _, id := range newIds {
+		if !containsInt(attached, id) {
+			attach = append(attach, id)
+		}
+	}
+	for _, id := range oldIds {
+		if containsInt(attached, id) && !containsInt(newIds, id) {
+			detach = append(detach, id)
+		}
+	}
+
+	if len(attach) > 0 {
+		if err := apiClient.AttachSecurityGroups(ctx, nodeId, attach); err != nil {
+			return fmt.Errorf("error attaching security groups %v to node %s: %s", attach, nodeId, err)
+		}
+	}
+	if len(detach) > 0 {
+		if err := apiClient.DetachSecurityGroups(ctx, nodeId, detach); err != nil {
+			return fmt.Errorf("error detaching security groups %v from node %s: %s", detach, nodeId, err)
+		}
+	}
+	return nil
+}
+
+// moveNodeVpc attaches the node to the planned vpc and then detaches it from
+// the previous one.
+func moveNodeVpc(ctx context.Context, apiClient *client.Client, d *schema.ResourceData, node *models.NodeDetail) error {
+	o, n := d.GetChange("vpc_id")
+	oldVpcId, newVpcId := o.(string), n.(string)
+	attached := func(vpcId string) bool {
+		for _, vpc := range node.Vpcs {
+			if strconv.Itoa(vpc.Network_id) == vpcId {
+				return true
+			}
+		}
+		return false
+	}
+
+	if newVpcId != "" && !attached(newVpcId) {
+		networkId, err := strconv.Atoi(newVpcId)
+		if err != nil {
+			return fmt.Errorf("vpc_id must be the numeric network id of a vpc, got %s", newVpcId)
+		}
+		if err := apiClient.AttachVpc(ctx, node.Id, networkId); err != nil {
+			return fmt.Errorf("error attaching node %d to vpc %s: %s", node.Id, newVpcId, err)
+		}
+	}
+	if oldVpcId != "" && attached(oldVpcId) {
+		networkId, _ := strconv.Atoi(oldVpcId)
+		if err := apiClient.DetachVpc(ctx, node.Id, networkId); err != nil {
+			return fmt.Errorf("error detaching node %d from vpc %s: %s", node.Id, oldVpcId, err)
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// nodePendingStatuses are the transitional states a node passes through on
+// its way to a stable state. Any status outside of these and the target is
+// treated as a failure.
+var nodePendingStatuses = []string{"Creating", "Starting", "Stopping", "Rebooting", "Reinstalling", "Upgrading"}
+
+func nodeStatusRefreshFunc(ctx context.Context, apiClient *client.Client, nodeId string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		node, err := apiClient.GetNode(ctx, nodeId)
+		if err != nil {
+			return nil, "", err
+		}
+		log.Printf("[INFO] node %s status %s", nodeId, node.Status)
+		return node, node.Status, nil
+	}
+}
+
+// WaitForNodeStatus polls the node until it reports the target status. On
+// timeout the last status observed is included in the error.
+func WaitForNodeStatus(ctx context.Context, apiClient *client.Client, nodeId string, target string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    nodePendingStatuses,
+		Target:     []string{target},
+		Refresh:    nodeStatusRefreshFunc(ctx, apiClient, nodeId),
+		Timeout:    timeout,
+		Delay:      10 * time.Second,
+		MinTimeout: 5 * time.Second,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		if timeoutErr, ok := err.(*resource.TimeoutError); ok {
+			return fmt.Errorf("timed out after %s waiting for node %s to become %s, last status: %s", timeout, nodeId, target, timeoutErr.LastState)
+		}
+		return fmt.Errorf("error waiting for node %s to become %s: %s", nodeId, target, err)
+	}
+	return nil
+}
+
+// WaitForNodeTransition polls the node until it leaves the from status it was
+// in when an action was sent and then settles on target. The node reports from
+// until the API starts the action, so from is pending until the node has left
+// it, even when it is the target as for a reboot.
+func WaitForNodeTransition(ctx context.Context, apiClient *client.Client, nodeId string, from string, target string, timeout time.Duration) error {
+	notStarted := from + " (action not started)"
+	refresh := nodeStatusRefreshFunc(ctx, apiClient, nodeId)
+	left := false
+	stateConf := &resource.StateChangeConf{
+		Pending: append([]string{notStarted}, nodePendingStatuses...),
+		Target:  []string{target},
+		Refresh: func() (interface{}, string, error) {
+			node, status, err := refresh()
+			if err != nil {
+				return nil, "", err
+			}
+			left = left || status != from
+			if !left {
+				return node, notStarted, nil
+			}
+			return node, status, nil
+		},
+		Timeout:    timeout,
+		Delay:      10 * time.Second,
+		MinTimeout: 5 * time.Second,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		if timeoutErr, ok := err.(*resource.TimeoutError); ok {
+			return fmt.Errorf("timed out after %s waiting for node %s to become %s, last status: %s", timeout, nodeId, target, timeoutErr.LastState)
+		}
+		return fmt.Errorf("error waiting for node %s to become %s: %s", nodeId, target, err)
+	}
+	return nil
+}
+
+// setNodePower sends a power_on or power_off action and waits for the node to
+// settle on the status it leads to.
+func setNodePower(ctx context.Context, apiClient *client.Client, nodeId string, action string, name string, timeout time.Duration) error {
+	log.Printf("[INFO] %s node %s", action, nodeId)
+	if _, err := apiClient.UpdateNode(ctx, nodeId, action, name); err != nil {
+		return fmt.Errorf("error running %s on node %s: %s", action, nodeId, err)
+	}
+	// The node starts in the status of the other power action.
+	from := powerStatuses["power_on"]
+	if action == "power_on" {
+		from = powerStatuses["power_off"]
+	}
+	return WaitForNodeTransition(ctx, apiClient, nodeId, from, powerStatuses[action], timeout)
+}
+
+// resourceImportNode accepts <node_id>, or <location>/<node_id> for a node
+// outside the provider location. It fills in the create time arguments the
+// API reports, Read leaves them alone.
+func resourceImportNode(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	nodeId := d.Id()
+	if parts := strings.Split(nodeId, "/"); len(parts) == 2 {
+		if !containsString(constants.Locations, parts[0]) {
+			return nil, fmt.Errorf("unexpected location %s in ID (%s), expected one of %v", parts[0], nodeId, constants.Locations)
+		}
+		d.Set("location", parts[0])
+		nodeId = parts[1]
+	}
+	if !nodeIdPattern.MatchString(nodeId) {
+		return nil, fmt.Errorf("unexpected format of ID (%s), expected <node_id> or <location>/<node_id>", d.Id())
+	}
+	d.SetId(nodeId)
+
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	node, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		if client.IsNotFound(err) {
+			return nil, fmt.Errorf("node %s not found in %s, import a node of another location as <location>/<node_id>", nodeId, apiClient.Location)
+		}
+		return nil, fmt.Errorf("error importing node %s: %s", nodeId, err)
+	}
+	if node.Image != nil {
+		d.Set("image", *node.Image)
+	}
+	if node.Region != nil {
+		d.Set("region", *node.Region)
+	} else {
+		d.Set("region", apiClient.Location)
+	}
+	if node.Reserve_ip != nil {
+		d.Set("reserve_ip", *node.Reserve_ip)
+	}
+	if node.SSH_keys != nil {
+		if err := d.Set("ssh_keys", node.SSH_keys); err != nil {
+			return nil, err
+		}
+	}
+	if node.Default_public_ip != nil {
+		d.Set("default_public_ip", *node.Default_public_ip)
+	}
+	if node.Disable_password != nil {
+		d.Set("disable_password", *node.Disable_password)
+	}
+	if node.Is_ipv6_availed != nil {
+		d.Set("is_ipv6_availed", *node.Is_ipv6_availed)
+	}
+	d.Set("user_data", importedUserData)
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceReadNode(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	apiClient := m.(*client.Client)
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
 	var diags diag.Diagnostics
 	log.Printf("[info] inside read")
 	nodeId := d.Id()
+	d.Set("location", apiClient.Location)
 
-	node, err := apiClient.GetNode(nodeId)
+	node, err := apiClient.GetNode(ctx, nodeId)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if client.IsNotFound(err) {
+			log.Printf("[WARN] node %s not found, removing from state", nodeId)
 			d.SetId("")
-		} else {
-			return diag.Errorf("error finding Item with ID %s", nodeId)
-
-		}
-	}
-
-	data := node["data"].(map[string]interface{})
-	d.Set("name", data["name"].(string))
-	d.Set("label", data["label"].(string))
-	d.Set("plan", data["plan"].(string))
-	d.Set("backup", data["backup"].(bool))
-	d.Set("is_active", data["is_active"].(bool))
-	d.Set("created_at", data["created_at"].(string))
-	d.Set("memory", data["memory"].(string))
-	d.Set("status", data["status"].(string))
-	d.Set("disk", data["disk"].(string))
-	d.Set("price", data["price"].(string))
-	d.Set("lock_node", data["is_locked"].(bool))
-	d.Set("public_ip_address", data["public_ip_address"].(string))
-	d.Set("private_ip_address", data["private_ip_address"].(string))
-	d.Set("is_monitored", data["is_monitored"].(bool))
-	d.Set("is_bitninja_license_active", data["is_bitninja_license_active"].(bool))
-
-	if d.Get("status").(string) == "Running" {
-		d.Set("power_status", "power_on")
+			return diags
+		}
+		return diag.Errorf("error finding Item with ID %s: %s", nodeId, err)
+	}
+
+	d.Set("name", node.Name)
+	d.Set("label", node.Label)
+	d.Set("plan", node.Plan)
+	vpcIds, vpcPrivateIps := []string{}, []string{}
+	for _, vpc := range node.Vpcs {
+		vpcIds = append(vpcIds, strconv.Itoa(vpc.Network_id))
+		vpcPrivateIps = append(vpcPrivateIps, vpc.Private_ip)
+	}
+	// Only the configured vpc is tracked, vpcs attached with
+	// e2e_vpc_attachment are left out so removing vpc_id still detaches.
+	if !containsString(vpcIds, d.Get("vpc_id").(string)) {
+		d.Set("vpc_id", "")
+	}
+	d.Set("vpc_private_ips", vpcPrivateIps)
+	securityGroupIds := attachedSecurityGroupIds(node)
+	if err := d.Set("security_group_ids", securityGroupIds); err != nil {
+		return diag.FromErr(err)
 	}
-	if d.Get("status").(string) == "Powered off" {
+	// Keep the configured group while it is attached, the node may have
+	// others from e2e_node_security_group_attachment.
+	if securityGroupId := d.Get("security_group_id").(int); !containsInt(securityGroupIds, securityGroupId) {
+		d.Set("security_group_id", node.Security_group_id)
+	}
+	// The API may report backup as null, keep the configured value then
+	// rather than reading it back as false. The create time arguments in
+	// nodeCreateArgs are not read back at all.
+	if node.Backup != nil {
+		d.Set("backup", *node.Backup)
+	}
+	d.Set("is_active", node.Is_active)
+	d.Set("created_at", node.Created_at)
+	d.Set("memory", node.Memory)
+	d.Set("status", node.Status)
+	d.Set("disk", node.Disk)
+	d.Set("price", node.Price)
+	d.Set("lock_node", node.Is_locked)
+	d.Set("public_ip_address", node.Public_ip_address)
+	d.Set("private_ip_address", node.Private_ip_address)
+	d.Set("is_monitored", node.Is_monitored)
+	d.Set("is_bitninja_license_active", node.Is_bitninja_license_active)
+
+	switch node.Status {
+	case "Running":
+		d.Set("power_status", "power_on")
+	case "Powered off":
 		d.Set("power_status", "power_off")
 	}
 
@@ -330,96 +793,78 @@ func resourceReadNode(ctx context.Context, d *schema.ResourceData, m interface{}
 
 func resourceUpdateNode(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	apiClient := m.(*client.Client)
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
 
 	nodeId := d.Id()
 
-	_, err := apiClient.GetNode(nodeId)
+	node, err := apiClient.GetNode(ctx, nodeId)
 	if err != nil {
 
 		return diag.Errorf("error finding Item with ID %s", nodeId)
 
 	}
 
-	if d.HasChange("power_status") {
-		nodestatus := d.Get("status").(string)
-		if nodestatus == "Creating" || nodestatus == "Reinstalling" {
-			return diag.Errorf("Node is in %s state", d.Get("status").(string))
+	// A locked node refuses every other change, so it is unlocked before them
+	// and locked only once they are done.
+	lockNode := d.Get("lock_node").(bool)
+	if d.HasChange("lock_node") && !lockNode {
+		if err := setNodeLock(ctx, apiClient, d, false); err != nil {
+			return diag.FromErr(err)
 		}
-		if d.Get("lock_node").(bool) == true {
-			return diag.Errorf("cannot change the power status as the node is locked")
-		}
-		log.Printf("[INFO] %s ", d.Get("power_status").(string))
-		apiClient.UpdateNode(nodeId, d.Get("power_status").(string), d.Get("name").(string))
+		node.Is_locked = false
 	}
 
-	if d.HasChange("lock_node") {
-		if d.Get("status").(string) == "Creating" || d.Get("status").(string) == "Reinstalling" {
-			return diag.Errorf("Cannot update as the node is in %s state", d.Get("status").(string))
-		}
-		if d.Get("lock_node").(bool) == true {
-			_, err := apiClient.UpdateNode(nodeId, "lock_vm", "")
-			if err != nil {
-				return diag.FromErr(err)
-			}
+	if d.HasChange("plan") {
+		if node.Is_locked {
+			d.Partial(true)
+			return diag.Errorf("cannot change the plan as the node is locked")
 		}
-		if d.Get("lock_node").(bool) == false {
-			_, err := apiClient.UpdateNode(nodeId, "unlock_vm", d.Get("name").(string))
-			if err != nil {
-				return diag.FromErr(err)
-			}
+		if err := resizeNode(ctx, apiClient, d, node.Status); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
 		}
 	}
 
-	if d.HasChange("reboot_node") {
-		if d.Get("status").(string) == "Creating" || d.Get("status").(string) == "Reinstalling" {
-			return diag.Errorf("Cannot update as the node is in %s state", d.Get("status").(string))
+	if d.HasChange("power_status") {
+		action := d.Get("power_status").(string)
+		if node.Status != "Running" && node.Status != "Powered off" {
+			d.Partial(true)
+			return diag.Errorf("cannot %s node %s as it is in %s state", action, nodeId, node.Status)
 		}
-		if d.Get("reboot_node").(bool) == true {
-			if d.Get("status").(string) == "Powered off" {
-				return diag.Errorf("cannot reboot as the node is powered off")
-			}
-			_, err := apiClient.UpdateNode(nodeId, "reboot", d.Get("name").(string))
-			d.Set("reboot_node", false)
-			if err != nil {
+		if node.Is_locked {
+			d.Partial(true)
+			return diag.Errorf("cannot change the power status as the node is locked")
+		}
+		if node.Status != powerStatuses[action] {
+			if err := setNodePower(ctx, apiClient, nodeId, action, node.Name, d.Timeout(schema.TimeoutUpdate)); err != nil {
+				d.Partial(true)
 				return diag.FromErr(err)
 			}
 		}
 	}
-	if d.HasChange("reinstall_node") {
-		if d.Get("status").(string) == "Creating" {
-			return diag.Errorf("Node is in creating state")
+
+	if d.HasChanges("security_group_id", "security_group_ids") {
+		if err := updateNodeSecurityGroups(ctx, apiClient, d, attachedSecurityGroupIds(node)); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
 		}
-		if d.Get("status").(string) == "Reinstalling" {
-			return diag.Errorf("Node already in Reinstalling state")
+	}
+
+	if d.HasChange("vpc_id") {
+		if node.Is_locked {
+			d.Partial(true)
+			return diag.Errorf("cannot change the vpc as the node is locked")
 		}
-		if d.Get("reinstall_node").(bool) == true {
-			if d.Get("status").(string) == "Powered off" {
-				d.Set("reinstall_node", false)
-				return diag.Errorf("cannot reinstall as the node is powered off")
-			}
-			if d.Get("status").(string) == "Reinstalling" {
-				d.Set("reinstall_node", false)
-				return diag.Errorf("Node already in Reinstalling state")
-			}
-			_, err := apiClient.UpdateNode(nodeId, "reinstall", d.Get("name").(string))
-			d.Set("reinstall_node", false)
-			if err != nil {
-				return diag.FromErr(err)
-			}
+		if err := moveNodeVpc(ctx, apiClient, d, node); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
 		}
 	}
 
-	if d.HasChange("save_image") {
-		if d.Get("save_image") == true {
-			if d.Get("save_image_name").(string) == "" {
-				return diag.Errorf("save_image_name empty")
-			}
-			_, err := apiClient.UpdateNode(nodeId, "save_images", d.Get("save_image_name").(string))
-			d.Set("save_image", false)
-			if err != nil {
-				return diag.FromErr(err)
-			}
+	if d.HasChange("lock_node") && lockNode {
+		if err := setNodeLock(ctx, apiClient, d, true); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
 		}
 	}
 
@@ -427,31 +872,109 @@ func resourceUpdateNode(ctx context.Context, d *schema.ResourceData, m interface
 
 }
 
-func resourceDeleteNode(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*client.Client)
-	var diags diag.Diagnostics
+// setNodeLock sends lock_vm or unlock_vm for the node.
+func setNodeLock(ctx context.Context, apiClient *client.Client, d *schema.ResourceData, lock bool) error {
+	if status := d.Get("status").(string); status == "Creating" || status == "Reinstalling" {
+		return fmt.Errorf("Cannot update as the node is in %s state", status)
+	}
+	if lock {
+		_, err := apiClient.UpdateNode(ctx, d.Id(), "lock_vm", "")
+		return err
+	}
+	_, err := apiClient.UpdateNode(ctx, d.Id(), "unlock_vm", d.Get("name").(string))
+	return err
+}
+
+// resizeNode moves the node to the planned plan. The API only resizes powered
+// off nodes, so a running node is powered off first and started again once
+// the resize is done, whether it succeeded or not.
+func resizeNode(ctx context.Context, apiClient *client.Client, d *schema.ResourceData, status string) error {
 	nodeId := d.Id()
+	name := d.Get("name").(string)
+	oldPlan, newPlan := d.GetChange("plan")
+	timeout := d.Timeout(schema.TimeoutUpdate)
 
-	err := apiClient.DeleteNode(nodeId)
-	if err != nil {
-		return diag.FromErr(err)
+	if status != "Running" && status != "Powered off" {
+		return fmt.Errorf("cannot change the plan as the node is in %s state", status)
 	}
-	d.SetId("")
-	return diags
+	wasRunning := status == "Running"
+	if wasRunning {
+		if _, err := apiClient.UpdateNode(ctx, nodeId, "power_off", name); err != nil {
+			return fmt.Errorf("error powering off node %s before resizing: %s", nodeId, err)
+		}
+		if err := WaitForNodeTransition(ctx, apiClient, nodeId, "Running", "Powered off", timeout); err != nil {
+			return err
+		}
+	}
+
+	upgrade := models.NodeUpgrade{
+		Plan:  newPlan.(string),
+		Image: d.Get("image").(string),
+	}
+	_, upgradeErr := apiClient.UpgradeNode(ctx, nodeId, &upgrade)
+	if upgradeErr == nil {
+		upgradeErr = waitForNodeUpgrade(ctx, apiClient, nodeId, upgrade.Plan, timeout)
+	}
+
+	if wasRunning {
+		if _, err := apiClient.UpdateNode(ctx, nodeId, "power_on", name); err != nil {
+			return fmt.Errorf("error powering node %s back on after resizing: %s", nodeId, err)
+		}
+		if err := WaitForNodeTransition(ctx, apiClient, nodeId, "Powered off", "Running", timeout); err != nil {
+			return err
+		}
+	}
+
+	if upgradeErr != nil {
+		var apiErr *client.APIError
+		if errors.As(upgradeErr, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+			return fmt.Errorf("plan %s is not a valid upgrade from %s for image %s: %s", upgrade.Plan, oldPlan, upgrade.Image, apiErr.Message)
+		}
+		return fmt.Errorf("error resizing node %s to plan %s: %s", nodeId, upgrade.Plan, upgradeErr)
+	}
+	return nil
 }
 
-func resourceExistsNode(d *schema.ResourceData, m interface{}) (bool, error) {
-	apiClient := m.(*client.Client)
+// waitForNodeUpgrade waits for an accepted upgrade to complete. The node is
+// already Powered off when the upgrade is sent, so it first waits for the
+// node to report the new plan or the Upgrading status before waiting for it
+// to be Powered off again.
+func waitForNodeUpgrade(ctx context.Context, apiClient *client.Client, nodeId string, plan string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"Powered off"},
+		Target:  []string{"Upgrading", "Upgraded"},
+		Refresh: func() (interface{}, string, error) {
+			node, err := apiClient.GetNode(ctx, nodeId)
+			if err != nil {
+				return nil, "", err
+			}
+			log.Printf("[INFO] node %s status %s plan %s", nodeId, node.Status, node.Plan)
+			if node.Plan == plan {
+				return node, "Upgraded", nil
+			}
+			return node, node.Status, nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 5 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		if timeoutErr, ok := err.(*resource.TimeoutError); ok {
+			return fmt.Errorf("timed out after %s waiting for node %s to start upgrading to plan %s, last status: %s", timeout, nodeId, plan, timeoutErr.LastState)
+		}
+		return fmt.Errorf("error waiting for node %s to start upgrading to plan %s: %s", nodeId, plan, err)
+	}
+	return WaitForNodeStatus(ctx, apiClient, nodeId, "Powered off", timeout)
+}
 
+func resourceDeleteNode(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
 	nodeId := d.Id()
-	_, err := apiClient.GetNode(nodeId)
 
+	err := apiClient.DeleteNode(ctx, nodeId)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			return false, nil
-		} else {
-			return false, err
-		}
+		return diag.FromErr(err)
 	}
-	return true, nil
+	d.SetId("")
+	return diags
 }
@@ -0,0 +1,97 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// nodeActionSchema returns the arguments shared by the node action resources
+// together with the given ones. Every argument forces a new resource, so that
+// changing any of them runs the action again.
+func nodeActionSchema(nodeIdDescription string, extra map[string]*schema.Schema) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"node_id": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringMatch(nodeIdPattern, "expected a numeric node id"),
+			Description:  nodeIdDescription,
+		},
+		"location": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice(constants.Locations, false),
+			Description:  "Location of the node. Defaults to the provider location",
+		},
+		"triggers": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			ForceNew:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Arbitrary values that run the action again when changed",
+		},
+	}
+	for k, v := range extra {
+		s[k] = v
+	}
+	return s
+}
+
+// runNodeAction runs action on the node and waits for the node to leave the
+// status it was in and settle on target. When required is not empty the node
+// must be in that status for the action to be sent.
+func runNodeAction(ctx context.Context, apiClient *client.Client, nodeId string, action string, required string, target string, d *schema.ResourceData) error {
+	node, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		return fmt.Errorf("error finding node with ID %s: %s", nodeId, err)
+	}
+	if node.Is_locked {
+		return fmt.Errorf("cannot %s node %s as it is locked", action, nodeId)
+	}
+	if required != "" && node.Status != required {
+		return fmt.Errorf("cannot %s node %s as it is in %s state, it must be %s", action, nodeId, node.Status, required)
+	}
+
+	log.Printf("[INFO] running %s on node %s", action, nodeId)
+	if _, err := apiClient.UpdateNode(ctx, nodeId, action, node.Name); err != nil {
+		return fmt.Errorf("error running %s on node %s: %s", action, nodeId, err)
+	}
+	return WaitForNodeTransition(ctx, apiClient, nodeId, node.Status, target, d.Timeout(schema.TimeoutCreate))
+}
+
+// resourceReadNodeAction only checks that the node still exists. The action
+// itself leaves nothing behind to read.
+func resourceReadNodeAction(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+	d.Set("location", apiClient.Location)
+
+	nodeId := d.Get("node_id").(string)
+	_, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		if client.IsNotFound(err) {
+			log.Printf("[WARN] node %s not found, removing %s from state", nodeId, d.Id())
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error finding node with ID %s: %s", nodeId, err)
+	}
+	return diags
+}
+
+// resourceDeleteNodeAction forgets the action, the node is left as it is.
+func resourceDeleteNodeAction(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	d.SetId("")
+	return diags
+}
@@ -0,0 +1,70 @@
+package node
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// powerStatuses maps the accepted power_status values to the status the node
+// settles on.
+var powerStatuses = map[string]string{
+	"power_on":  "Running",
+	"power_off": "Powered off",
+}
+
+func ResourceNodePower() *schema.Resource {
+	return &schema.Resource{
+		Schema: nodeActionSchema("id of the node to power on or off. Set power_status of the matching e2e_node to the same value, or ignore changes to it, so the two do not undo each other", map[string]*schema.Schema{
+			"power_status": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"power_on", "power_off"}, false),
+				Description:  "power_on to start the node and power_off to power off the node. Nothing is sent when the node already is in that state",
+			},
+		}),
+
+		CreateContext: resourceCreateNodePower,
+		ReadContext:   resourceReadNodeAction,
+		DeleteContext: resourceDeleteNodeAction,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+		},
+	}
+}
+
+func resourceCreateNodePower(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	d.Set("location", apiClient.Location)
+
+	log.Printf("[INFO] inside node power create")
+	nodeId := d.Get("node_id").(string)
+	action := d.Get("power_status").(string)
+	target := powerStatuses[action]
+
+	node, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		return diag.Errorf("error finding node with ID %s: %s", nodeId, err)
+	}
+	switch node.Status {
+	case target:
+		log.Printf("[INFO] node %s already %s", nodeId, target)
+	case "Running", "Powered off":
+		if err := runNodeAction(ctx, apiClient, nodeId, action, node.Status, target, d); err != nil {
+			return diag.FromErr(err)
+		}
+	default:
+		return diag.Errorf("cannot %s node %s as it is in %s state", action, nodeId, node.Status)
+	}
+	d.SetId(resource.PrefixedUniqueId(nodeId + "-" + action + "-"))
+
+	return resourceReadNodeAction(ctx, d, m)
+}
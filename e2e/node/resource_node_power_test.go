@@ -0,0 +1,57 @@
+package node_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccNodePower_basic(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodePowerConfig("power_on"),
+				Check:  testAccCheckNodeActions(server, "e2e_node.test"),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodePowerConfig("power_off"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node_power.test", "power_status", "power_off"),
+					testAccCheckNodeActions(server, "e2e_node.test", "power_off"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodePowerConfig("power_on"),
+				Check:  testAccCheckNodeActions(server, "e2e_node.test", "power_off", "power_on"),
+			},
+		},
+	})
+}
+
+func testAccNodePowerConfig(powerStatus string) string {
+	return fmt.Sprintf(`
+resource "e2e_node" "test" {
+  name  = "acc-node"
+  label = "acc"
+  plan  = "C2.40GB"
+  image = "Ubuntu-22.04-Distro"
+
+  lifecycle {
+    ignore_changes = [power_status]
+  }
+}
+
+resource "e2e_node_power" "test" {
+  node_id      = e2e_node.test.id
+  power_status = %q
+}
+`, powerStatus)
+}
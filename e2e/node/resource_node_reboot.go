@@ -0,0 +1,40 @@
+package node
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func ResourceNodeReboot() *schema.Resource {
+	return &schema.Resource{
+		Schema: nodeActionSchema("id of the node to reboot. The node must be running. If you have an active disk-intensive process such as database, backups running, then a rebooting may lead to data corruption and data loss (best option is to reboot the machine from within Operating System)", nil),
+
+		CreateContext: resourceCreateNodeReboot,
+		ReadContext:   resourceReadNodeAction,
+		DeleteContext: resourceDeleteNodeAction,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+		},
+	}
+}
+
+func resourceCreateNodeReboot(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	d.Set("location", apiClient.Location)
+
+	log.Printf("[INFO] inside node reboot create")
+	nodeId := d.Get("node_id").(string)
+	if err := runNodeAction(ctx, apiClient, nodeId, "reboot", "Running", "Running", d); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(resource.PrefixedUniqueId(nodeId + "-reboot-"))
+
+	return resourceReadNodeAction(ctx, d, m)
+}
@@ -0,0 +1,119 @@
+package node_test
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccNodeReboot_triggers(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeRebootConfig("1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node_reboot.test", "location", "Delhi"),
+					resource.TestCheckResourceAttr("e2e_node.test", "status", "Running"),
+					testAccCheckNodeActions(server, "e2e_node.test", "reboot"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeRebootConfig("1"),
+				Check:  testAccCheckNodeActions(server, "e2e_node.test", "reboot"),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeRebootConfig("2"),
+				Check:  testAccCheckNodeActions(server, "e2e_node.test", "reboot", "reboot"),
+			},
+		},
+	})
+}
+
+func TestAccNodeReboot_slowStart(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	// The node still reports Running for a few reads after the reboot is
+	// accepted.
+	server.ActionStartPolls = 3
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeRebootConfig("1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNodeActions(server, "e2e_node.test", "reboot"),
+					testAccCheckNodeSettled(server, "e2e_node.test"),
+				),
+			},
+		},
+	})
+}
+
+func testAccNodeRebootConfig(trigger string) string {
+	return fmt.Sprintf(`
+resource "e2e_node" "test" {
+  name  = "acc-node"
+  label = "acc"
+  plan  = "C2.40GB"
+  image = "Ubuntu-22.04-Distro"
+}
+
+resource "e2e_node_reboot" "test" {
+  node_id = e2e_node.test.id
+  triggers = {
+    kernel = %q
+  }
+}
+`, trigger)
+}
+
+// testAccCheckNodeActions checks the actions the fake API received for the
+// node, in order.
+func testAccCheckNodeActions(server *fakeapi.Server, name string, expected ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		id, err := strconv.Atoi(s.RootModule().Resources[name].Primary.ID)
+		if err != nil {
+			return err
+		}
+		node, ok := server.Node(id)
+		if !ok {
+			return fmt.Errorf("node %d not found", id)
+		}
+		if actions := node.Actions(); !reflect.DeepEqual(actions, expected) {
+			return fmt.Errorf("expected actions %v on node %d, got %v", expected, id, actions)
+		}
+		return nil
+	}
+}
+
+// testAccCheckNodeSettled checks that the fake API has finished every action
+// sent to the node.
+func testAccCheckNodeSettled(server *fakeapi.Server, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		id, err := strconv.Atoi(s.RootModule().Resources[name].Primary.ID)
+		if err != nil {
+			return err
+		}
+		node, ok := server.Node(id)
+		if !ok {
+			return fmt.Errorf("node %d not found", id)
+		}
+		if !node.Settled() {
+			return fmt.Errorf("node %d is still %s, with an action in progress", id, node.Status)
+		}
+		return nil
+	}
+}
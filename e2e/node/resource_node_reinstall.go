@@ -0,0 +1,40 @@
+package node
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func ResourceNodeReinstall() *schema.Resource {
+	return &schema.Resource{
+		Schema: nodeActionSchema("id of the node to reinstall. The node must be running. Reinstalling deletes all the data of the node permanently", nil),
+
+		CreateContext: resourceCreateNodeReinstall,
+		ReadContext:   resourceReadNodeAction,
+		DeleteContext: resourceDeleteNodeAction,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+func resourceCreateNodeReinstall(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	d.Set("location", apiClient.Location)
+
+	log.Printf("[INFO] inside node reinstall create")
+	nodeId := d.Get("node_id").(string)
+	if err := runNodeAction(ctx, apiClient, nodeId, "reinstall", "Running", "Running", d); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(resource.PrefixedUniqueId(nodeId + "-reinstall-"))
+
+	return resourceReadNodeAction(ctx, d, m)
+}
@@ -0,0 +1,56 @@
+package node_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccNodeReinstall_basic(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeReinstallConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "status", "Running"),
+					testAccCheckNodeActions(server, "e2e_node.test", "reinstall"),
+				),
+			},
+			{
+				Config:      acctest.ProviderConfig(server) + testAccNodeReinstallConfig(true),
+				ExpectError: regexp.MustCompile(`cannot reinstall node \d+ as it is locked`),
+			},
+			{
+				// Unlock the node again, a locked node cannot be destroyed.
+				Config: acctest.ProviderConfig(server) + testAccNodeReinstallConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "lock_node", "false"),
+					testAccCheckNodeActions(server, "e2e_node.test", "reinstall", "lock_vm", "unlock_vm", "reinstall"),
+				),
+			},
+		},
+	})
+}
+
+func testAccNodeReinstallConfig(locked bool) string {
+	trigger := "first"
+	if locked {
+		trigger = "second"
+	}
+	return testAccNodeConfig(locked) + `
+resource "e2e_node_reinstall" "test" {
+  node_id = e2e_node.test.id
+  triggers = {
+    run = "` + trigger + `"
+  }
+}
+`
+}
@@ -0,0 +1,149 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func ResourceNodeSecurityGroupAttachment() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"node_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(nodeIdPattern, "expected a numeric node id"),
+				Description:  "id of the node. Leave security_group_ids of the e2e_node unset, otherwise the two undo each other",
+			},
+			"security_group_id": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "id of the security group to attach to the node",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location of the node. Defaults to the provider location",
+			},
+		},
+
+		CreateContext: resourceCreateNodeSecurityGroupAttachment,
+		ReadContext:   resourceReadNodeSecurityGroupAttachment,
+		DeleteContext: resourceDeleteNodeSecurityGroupAttachment,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportNodeSecurityGroupAttachment,
+		},
+	}
+}
+
+func parseNodeSecurityGroupAttachmentId(id string) (string, int, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 || !nodeIdPattern.MatchString(parts[0]) {
+		return "", 0, fmt.Errorf("unexpected format of ID (%s), expected <node_id>/<security_group_id>", id)
+	}
+	securityGroupId, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", 0, fmt.Errorf("unexpected format of ID (%s), expected <node_id>/<security_group_id>", id)
+	}
+	return parts[0], securityGroupId, nil
+}
+
+func resourceCreateNodeSecurityGroupAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	d.Set("location", apiClient.Location)
+
+	log.Printf("[INFO] inside node security group attachment create")
+	nodeId := d.Get("node_id").(string)
+	securityGroupId := d.Get("security_group_id").(int)
+
+	node, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		return diag.Errorf("error finding node with ID %s: %s", nodeId, err)
+	}
+	if containsInt(attachedSecurityGroupIds(node), securityGroupId) {
+		return diag.Errorf("security group %d is already attached to node %s, import it with the ID %s/%d to manage it", securityGroupId, nodeId, nodeId, securityGroupId)
+	}
+
+	if err := apiClient.AttachSecurityGroups(ctx, nodeId, []int{securityGroupId}); err != nil {
+		return diag.Errorf("error attaching security group %d to node %s: %s", securityGroupId, nodeId, err)
+	}
+	d.SetId(fmt.Sprintf("%s/%d", nodeId, securityGroupId))
+
+	return resourceReadNodeSecurityGroupAttachment(ctx, d, m)
+}
+
+func resourceReadNodeSecurityGroupAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+	d.Set("location", apiClient.Location)
+
+	nodeId, securityGroupId, err := parseNodeSecurityGroupAttachmentId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	node, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		if client.IsNotFound(err) {
+			log.Printf("[WARN] node %s not found, removing security group attachment from state", nodeId)
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error finding node with ID %s: %s", nodeId, err)
+	}
+	if !containsInt(attachedSecurityGroupIds(node), securityGroupId) {
+		log.Printf("[WARN] security group %d no longer attached to node %s, removing from state", securityGroupId, nodeId)
+		d.SetId("")
+		return diags
+	}
+
+	d.Set("node_id", nodeId)
+	d.Set("security_group_id", securityGroupId)
+	return diags
+}
+
+func resourceDeleteNodeSecurityGroupAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+
+	nodeId, securityGroupId, err := parseNodeSecurityGroupAttachmentId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	node, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		if client.IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error finding node with ID %s: %s", nodeId, err)
+	}
+	if containsInt(attachedSecurityGroupIds(node), securityGroupId) {
+		if err := apiClient.DetachSecurityGroups(ctx, nodeId, []int{securityGroupId}); err != nil {
+			return diag.Errorf("error detaching security group %d from node %s: %s", securityGroupId, nodeId, err)
+		}
+	}
+	d.SetId("")
+	return diags
+}
+
+func resourceImportNodeSecurityGroupAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseNodeSecurityGroupAttachmentId(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
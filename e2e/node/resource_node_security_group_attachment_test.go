@@ -0,0 +1,109 @@
+package node_test
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccNodeSecurityGroupAttachment_basic(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeSecurityGroupAttachmentConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNodeSecurityGroups(server, "e2e_node.test", "150", "e2e_security_group.web"),
+					resource.TestCheckResourceAttr("e2e_node.test", "security_group_id", "150"),
+				),
+			},
+			{
+				ResourceName:      "e2e_node_security_group_attachment.web",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeSecurityGroupAttachmentConfig(false),
+				Check:  testAccCheckNodeSecurityGroups(server, "e2e_node.test", "150"),
+			},
+		},
+	})
+}
+
+func testAccNodeSecurityGroupAttachmentConfig(attached bool) string {
+	config := `
+resource "e2e_security_group" "web" {
+  name        = "web"
+  description = "web servers"
+
+  outbound_rule {
+    protocol_name = "All"
+  }
+}
+
+resource "e2e_node" "test" {
+  name  = "acc-node"
+  label = "acc"
+  plan  = "C2.40GB"
+  image = "Ubuntu-22.04-Distro"
+}
+`
+	if attached {
+		config += `
+resource "e2e_node_security_group_attachment" "web" {
+  node_id           = e2e_node.test.id
+  security_group_id = e2e_security_group.web.id
+}
+`
+	}
+	return config
+}
+
+// testAccCheckNodeSecurityGroups checks the groups attached to the node on
+// the fake API. Each group is either an id or the name of an
+// e2e_security_group resource.
+func testAccCheckNodeSecurityGroups(server *fakeapi.Server, name string, groups ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		id, err := strconv.Atoi(s.RootModule().Resources[name].Primary.ID)
+		if err != nil {
+			return err
+		}
+		node, ok := server.Node(id)
+		if !ok {
+			return fmt.Errorf("node %d not found", id)
+		}
+
+		expected := []int{}
+		for _, group := range groups {
+			if rs, ok := s.RootModule().Resources[group]; ok {
+				group = rs.Primary.ID
+			}
+			groupId, err := strconv.Atoi(group)
+			if err != nil {
+				return err
+			}
+			expected = append(expected, groupId)
+		}
+		attached := []int{}
+		for _, group := range node.Security_groups {
+			attached = append(attached, group.Id)
+		}
+		sort.Ints(expected)
+		sort.Ints(attached)
+		if !reflect.DeepEqual(attached, expected) {
+			return fmt.Errorf("expected security groups %v on node %d, got %v", expected, id, attached)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,725 @@
+package node_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccNode_basic(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "name", "acc-node"),
+					resource.TestCheckResourceAttr("e2e_node.test", "status", "Running"),
+					resource.TestCheckResourceAttr("e2e_node.test", "power_status", "power_on"),
+					resource.TestCheckResourceAttr("e2e_node.test", "location", "Delhi"),
+					resource.TestCheckResourceAttrSet("e2e_node.test", "public_ip_address"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "lock_node", "true"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "lock_node", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccNodeConfig(locked bool) string {
+	return fmt.Sprintf(`
+resource "e2e_node" "test" {
+  name      = "acc-node"
+  label     = "acc"
+  plan      = "C2.40GB"
+  image     = "Ubuntu-22.04-Distro"
+  lock_node = %t
+}
+`, locked)
+}
+
+func testAccCheckNodeDestroy(server *fakeapi.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "e2e_node" {
+				continue
+			}
+			id, err := strconv.Atoi(rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+			if _, ok := server.Node(id); ok {
+				return fmt.Errorf("node %s still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccNode_resize(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigPlan("C2.40GB"),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigPlan("C2.80GB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "plan", "C2.80GB"),
+					resource.TestCheckResourceAttr("e2e_node.test", "status", "Running"),
+				),
+			},
+			{
+				Config:      acctest.ProviderConfig(server) + testAccNodeConfigPlan("C2.40GB"),
+				ExpectError: regexp.MustCompile(`plan C2.40GB is not a valid upgrade from C2.80GB`),
+			},
+		},
+	})
+}
+
+func TestAccNode_resizeSlowUpgrade(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	// The node still reports its old plan, Powered off, for a few reads after
+	// the upgrade is accepted, and its old status after a power action.
+	// Powering it on before the upgrade started fails.
+	server.UpgradeStartPolls = 3
+	server.ActionStartPolls = 3
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigPlan("C2.40GB"),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigPlan("C2.80GB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "plan", "C2.80GB"),
+					resource.TestCheckResourceAttr("e2e_node.test", "status", "Running"),
+					testAccCheckNodeActions(server, "e2e_node.test", "power_off", "power_on"),
+				),
+			},
+		},
+	})
+}
+
+func testAccNodeConfigPlan(plan string) string {
+	return fmt.Sprintf(`
+resource "e2e_node" "test" {
+  name  = "acc-node"
+  label = "acc"
+  plan  = %q
+  image = "Ubuntu-22.04-Distro"
+}
+`, plan)
+}
+
+func TestAccNode_savedImage(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.ProviderConfig(server) + testAccNodeConfigSavedImage(true, 0),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`saved_image_template_id is required when is_saved_image is true`),
+			},
+			{
+				Config:      acctest.ProviderConfig(server) + testAccNodeConfigSavedImage(false, 9001),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`saved_image_template_id can only be set when is_saved_image is true`),
+			},
+			{
+				Config:      acctest.ProviderConfig(server) + testAccNodeConfigSavedImage(true, 4242),
+				ExpectError: regexp.MustCompile(`no saved image with template id 4242 found`),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigSavedImage(true, 9001),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "saved_image_template_id", "9001"),
+					testAccCheckNodeRequest(server, "e2e_node.test", func(request models.Node) error {
+						if !request.Is_saved_image || request.Saved_image_template_id != 9001 {
+							return fmt.Errorf("node was not launched from template 9001: %+v", request)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccNodeConfigSavedImage(isSavedImage bool, templateId int) string {
+	template := ""
+	if templateId != 0 {
+		template = fmt.Sprintf("saved_image_template_id = %d", templateId)
+	}
+	return fmt.Sprintf(`
+resource "e2e_node" "test" {
+  name           = "acc-node"
+  label          = "acc"
+  plan           = "C2.40GB"
+  image          = "Ubuntu-22.04-Distro"
+  is_saved_image = %t
+  %s
+}
+`, isSavedImage, template)
+}
+
+func testAccCheckNodeRequest(server *fakeapi.Server, name string, check func(request models.Node) error) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		id, err := strconv.Atoi(s.RootModule().Resources[name].Primary.ID)
+		if err != nil {
+			return err
+		}
+		node, ok := server.Node(id)
+		if !ok {
+			return fmt.Errorf("node %d not found", id)
+		}
+		return check(node.Request())
+	}
+}
+
+func TestAccNode_drift(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	var nodeId int
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "image", "Ubuntu-22.04-Distro"),
+					resource.TestCheckResourceAttr("e2e_node.test", "security_group_id", "150"),
+					func(s *terraform.State) error {
+						id, err := strconv.Atoi(s.RootModule().Resources["e2e_node.test"].Primary.ID)
+						nodeId = id
+						return err
+					},
+				),
+			},
+			{
+				ResourceName:            "e2e_node.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"is_saved_image", "enable_bitninja", "user_data"},
+			},
+			{
+				PreConfig: func() {
+					node, ok := server.Node(nodeId)
+					if !ok {
+						t.Fatalf("node %d not found", nodeId)
+					}
+					backup := true
+					node.Backup = &backup
+					server.SetNode(node)
+				},
+				Config:             acctest.ProviderConfig(server) + testAccNodeConfig(false),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// backup cannot be changed on an existing node, so the drift
+				// replaces it.
+				Config: acctest.ProviderConfig(server) + testAccNodeConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "backup", "false"),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["e2e_node.test"].Primary.ID; id == strconv.Itoa(nodeId) {
+							return fmt.Errorf("expected node %s to be replaced", id)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   acctest.ProviderConfig(server) + testAccNodeConfig(false),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccNode_nullFlags(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	var nodeId int
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigFlags,
+				Check: func(s *terraform.State) error {
+					id, err := strconv.Atoi(s.RootModule().Resources["e2e_node.test"].Primary.ID)
+					nodeId = id
+					return err
+				},
+			},
+			{
+				// A null flag says nothing about the node, it must not
+				// show up as a change of the configured value.
+				PreConfig: func() {
+					node, ok := server.Node(nodeId)
+					if !ok {
+						t.Fatalf("node %d not found", nodeId)
+					}
+					node.Image, node.Region, node.Reserve_ip = nil, nil, nil
+					node.Backup, node.Default_public_ip, node.Disable_password, node.Is_ipv6_availed = nil, nil, nil, nil
+					server.SetNode(node)
+				},
+				Config:   acctest.ProviderConfig(server) + testAccNodeConfigFlags,
+				PlanOnly: true,
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigFlags,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "backup", "true"),
+					resource.TestCheckResourceAttr("e2e_node.test", "disable_password", "true"),
+					resource.TestCheckResourceAttr("e2e_node.test", "is_ipv6_availed", "true"),
+					resource.TestCheckResourceAttr("e2e_node.test", "image", "Ubuntu-22.04-Distro"),
+				),
+			},
+			{
+				Config:      acctest.ProviderConfig(server) + strings.Replace(testAccNodeConfigFlags, "is_ipv6_availed  = true", "is_ipv6_availed  = false", 1),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`is_ipv6_availed cannot be changed on an existing node`),
+			},
+		},
+	})
+}
+
+const testAccNodeConfigFlags = `
+resource "e2e_node" "test" {
+  name             = "acc-node"
+  label            = "acc"
+  plan             = "C2.40GB"
+  image            = "Ubuntu-22.04-Distro"
+  backup           = true
+  disable_password = true
+  is_ipv6_availed  = true
+}
+`
+
+func TestAccNode_location(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigLocation,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "location", "Mumbai"),
+					resource.TestCheckResourceAttr("e2e_node.test", "region", "Mumbai"),
+					acctest.CheckRequestLocation(server, "nodes/", "Mumbai"),
+					testAccCheckNodeRequest(server, "e2e_node.test", func(request models.Node) error {
+						if request.Region != "Mumbai" {
+							return fmt.Errorf("expected region Mumbai in the request, got %q", request.Region)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName: "e2e_node.test",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return "Mumbai/" + s.RootModule().Resources["e2e_node.test"].Primary.ID, nil
+				},
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"is_saved_image", "enable_bitninja", "user_data"},
+			},
+			{
+				ResourceName:  "e2e_node.test",
+				ImportState:   true,
+				ImportStateId: "Chennai/1001",
+				ExpectError:   regexp.MustCompile(`unexpected location Chennai in ID \(Chennai/1001\)`),
+			},
+		},
+	})
+}
+
+const testAccNodeConfigLocation = `
+resource "e2e_node" "test" {
+  name     = "acc-node"
+  label    = "acc"
+  plan     = "C2.40GB"
+  image    = "Ubuntu-22.04-Distro"
+  location = "Mumbai"
+}
+`
+
+func TestAccNode_power(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.ProviderConfig(server) + testAccNodeConfigPower("poweroff"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`expected power_status to be one of \[power_on power_off\]`),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigPower("power_off"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "status", "Powered off"),
+					resource.TestCheckResourceAttr("e2e_node.test", "power_status", "power_off"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigPower("power_on"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "status", "Running"),
+					resource.TestCheckResourceAttr("e2e_node.test", "power_status", "power_on"),
+					testAccCheckNodeActions(server, "e2e_node.test", "power_off", "power_on"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNode_powerSlowStart(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	// The node still reports its old status for a few reads after a power
+	// action is accepted.
+	server.ActionStartPolls = 3
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigPower("power_off"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "status", "Powered off"),
+					testAccCheckNodeSettled(server, "e2e_node.test"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigPower("power_on"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "status", "Running"),
+					testAccCheckNodeSettled(server, "e2e_node.test"),
+					testAccCheckNodeActions(server, "e2e_node.test", "power_off", "power_on"),
+				),
+			},
+		},
+	})
+}
+
+func testAccNodeConfigPower(powerStatus string) string {
+	return fmt.Sprintf(`
+resource "e2e_node" "test" {
+  name         = "acc-node"
+  label        = "acc"
+  plan         = "C2.40GB"
+  image        = "Ubuntu-22.04-Distro"
+  power_status = %q
+}
+`, powerStatus)
+}
+
+func TestAccNode_unlockAndPowerOff(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigLockPower(true, "power_on"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "lock_node", "true"),
+					testAccCheckNodeActions(server, "e2e_node.test", "lock_vm"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigLockPower(false, "power_off"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "lock_node", "false"),
+					resource.TestCheckResourceAttr("e2e_node.test", "status", "Powered off"),
+					testAccCheckNodeActions(server, "e2e_node.test", "lock_vm", "unlock_vm", "power_off"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigLockPower(true, "power_on"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "lock_node", "true"),
+					resource.TestCheckResourceAttr("e2e_node.test", "status", "Running"),
+					testAccCheckNodeActions(server, "e2e_node.test", "lock_vm", "unlock_vm", "power_off", "power_on", "lock_vm"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigLockPower(false, "power_on"),
+			},
+		},
+	})
+}
+
+func testAccNodeConfigLockPower(locked bool, powerStatus string) string {
+	return fmt.Sprintf(`
+resource "e2e_node" "test" {
+  name         = "acc-node"
+  label        = "acc"
+  plan         = "C2.40GB"
+  image        = "Ubuntu-22.04-Distro"
+  lock_node    = %t
+  power_status = %q
+}
+`, locked, powerStatus)
+}
+
+func TestAccNode_userData(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	userData := "#cloud-config\npackages:\n  - nginx\n"
+	hash := sha256.Sum256([]byte(userData))
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.ProviderConfig(server) + testAccNodeConfigUserData(strings.Repeat("#", 16*1024+1)),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`expected length of user_data to be in the range \(0 - 16384\)`),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigUserData(userData),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "user_data", hex.EncodeToString(hash[:])),
+					testAccCheckNodeRequest(server, "e2e_node.test", func(request models.Node) error {
+						if request.User_data != userData {
+							return fmt.Errorf("expected user_data %q to be sent, got %q", userData, request.User_data)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config:             acctest.ProviderConfig(server) + testAccNodeConfigUserData(userData+"  - git\n"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				ResourceName:            "e2e_node.test",
+				ImportState:             true,
+				ImportStatePersist:      true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"is_saved_image", "enable_bitninja", "user_data"},
+			},
+			{
+				// The API does not return user_data, the imported node must
+				// not be replaced because of it.
+				Config:   acctest.ProviderConfig(server) + testAccNodeConfigUserData(userData),
+				PlanOnly: true,
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigUserData(userData),
+				Check:  resource.TestCheckResourceAttr("e2e_node.test", "user_data", "imported"),
+			},
+		},
+	})
+}
+
+func TestAccNode_addUserData(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfig(false),
+				Check:  resource.TestCheckResourceAttr("e2e_node.test", "user_data", ""),
+			},
+			{
+				// The node was created without user_data, adding it must
+				// replace the node so that it runs.
+				Config:             acctest.ProviderConfig(server) + testAccNodeConfigUserData("#!/bin/sh\necho hello\n"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccNodeConfigUserData(userData string) string {
+	return fmt.Sprintf(`
+resource "e2e_node" "test" {
+  name      = "acc-node"
+  label     = "acc"
+  plan      = "C2.40GB"
+  image     = "Ubuntu-22.04-Distro"
+  user_data = %q
+}
+`, userData)
+}
+
+func TestAccNode_securityGroups(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigSecurityGroups(`security_group_ids = [150, e2e_security_group.web.id]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "security_group_ids.#", "2"),
+					testAccCheckNodeSecurityGroups(server, "e2e_node.test", "150", "e2e_security_group.web"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigSecurityGroups(`security_group_id = e2e_security_group.web.id`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("e2e_node.test", "security_group_id", "e2e_security_group.web", "id"),
+					resource.TestCheckResourceAttr("e2e_node.test", "security_group_ids.#", "1"),
+					testAccCheckNodeSecurityGroups(server, "e2e_node.test", "e2e_security_group.web"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigSecurityGroups(`security_group_id = 150`),
+				Check:  testAccCheckNodeSecurityGroups(server, "e2e_node.test", "150"),
+			},
+		},
+	})
+}
+
+func testAccNodeConfigSecurityGroups(securityGroups string) string {
+	return fmt.Sprintf(`
+resource "e2e_security_group" "web" {
+  name        = "web"
+  description = "web servers"
+
+  outbound_rule {
+    protocol_name = "All"
+  }
+}
+
+resource "e2e_node" "test" {
+  name  = "acc-node"
+  label = "acc"
+  plan  = "C2.40GB"
+  image = "Ubuntu-22.04-Distro"
+
+  %s
+}
+`, securityGroups)
+}
+
+func TestAccNode_moveVpc(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	var nodeId string
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckNodeDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigVpc(`"301"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "vpc_id", "301"),
+					resource.TestCheckResourceAttr("e2e_node.test", "vpc_private_ips.#", "1"),
+					func(s *terraform.State) error {
+						nodeId = s.RootModule().Resources["e2e_node.test"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigVpc(`e2e_vpc.app.id`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("e2e_node.test", "vpc_id", "e2e_vpc.app", "id"),
+					resource.TestCheckResourceAttr("e2e_node.test", "vpc_private_ips.#", "1"),
+					resource.TestMatchResourceAttr("e2e_node.test", "vpc_private_ips.0", regexp.MustCompile(`^10\.40\.0\.\d+$`)),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["e2e_node.test"].Primary.ID; id != nodeId {
+							return fmt.Errorf("expected node %s to be moved in place, got node %s", nodeId, id)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccNodeConfigVpc(`null`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_node.test", "vpc_id", ""),
+					resource.TestCheckResourceAttr("e2e_node.test", "vpc_private_ips.#", "0"),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["e2e_node.test"].Primary.ID; id != nodeId {
+							return fmt.Errorf("expected node %s to be detached in place, got node %s", nodeId, id)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccNodeConfigVpc(vpcId string) string {
+	return fmt.Sprintf(`
+resource "e2e_vpc" "app" {
+  name      = "app"
+  ipv4_cidr = "10.40.0.0/23"
+}
+
+resource "e2e_node" "test" {
+  name   = "acc-node"
+  label  = "acc"
+  plan   = "C2.40GB"
+  image  = "Ubuntu-22.04-Distro"
+  vpc_id = %s
+}
+`, vpcId)
+}
@@ -0,0 +1,145 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func DataSourcePlans() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"image": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only plans the image (for example Ubuntu-22.04-Distro) can be launched on are returned",
+			},
+			"min_cpu": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Only plans with at least this many vCPUs are returned",
+			},
+			"min_memory": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "Only plans with at least this much memory, in GB, are returned",
+			},
+			"plan_list": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Matching plans, cheapest first",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"series": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"location": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cpu": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"memory": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"disk": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"gpu": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"price_per_hour": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"price_per_month": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"images": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+
+		ReadContext: dataSourceReadPlans,
+	}
+}
+
+func dataSourceReadPlans(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client)
+	var diags diag.Diagnostics
+
+	log.Printf("[INFO] Inside plans data source ")
+	image := d.Get("image").(string)
+	plans, err := apiClient.GetPlans(ctx, image)
+	if err != nil {
+		return diag.Errorf("error listing plans: %s", err)
+	}
+
+	minCpu := d.Get("min_cpu").(int)
+	minMemory := d.Get("min_memory").(float64)
+	matched := []models.Plan{}
+	for _, plan := range plans {
+		if plan.Cpu < minCpu || plan.Memory < minMemory {
+			continue
+		}
+		matched = append(matched, plan)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].Price_per_month != matched[j].Price_per_month {
+			return matched[i].Price_per_month < matched[j].Price_per_month
+		}
+		return matched[i].Name < matched[j].Name
+	})
+
+	if err := d.Set("plan_list", flattenPlans(matched)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("plan_list-%s-%s-%d-%g", apiClient.Location, image, minCpu, minMemory))
+
+	return diags
+}
+
+func flattenPlans(plans []models.Plan) []interface{} {
+	ois := make([]interface{}, 0, len(plans))
+	for _, plan := range plans {
+		ois = append(ois, map[string]interface{}{
+			"name":            plan.Name,
+			"series":          plan.Series,
+			"location":        plan.Location,
+			"cpu":             plan.Cpu,
+			"memory":          plan.Memory,
+			"disk":            plan.Disk,
+			"gpu":             plan.Gpu,
+			"price_per_hour":  plan.Price_per_hour,
+			"price_per_month": plan.Price_per_month,
+			"images":          plan.Images,
+		})
+	}
+	return ois
+}
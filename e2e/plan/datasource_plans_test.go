@@ -0,0 +1,39 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourcePlans_filter(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_plans" "all" {}
+
+data "e2e_plans" "cheapest" {
+  image      = "CentOS-7.5-Distro"
+  min_cpu    = 16
+  min_memory = 64
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_plans.all", "plan_list.#", "4"),
+					resource.TestCheckResourceAttr("data.e2e_plans.all", "plan_list.0.name", "C2.40GB"),
+					resource.TestCheckResourceAttr("data.e2e_plans.all", "plan_list.0.location", "Delhi"),
+					resource.TestCheckResourceAttr("data.e2e_plans.cheapest", "plan_list.#", "2"),
+					resource.TestCheckResourceAttr("data.e2e_plans.cheapest", "plan_list.0.name", "C2.80GB"),
+					resource.TestCheckResourceAttr("data.e2e_plans.cheapest", "plan_list.0.cpu", "24"),
+				),
+			},
+		},
+	})
+}
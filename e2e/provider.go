@@ -1,13 +1,18 @@
 package e2e
 
 import (
+	"time"
+
 	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
 	"github.com/e2eterraformprovider/terraform-provider-e2e/e2e/image"
 	"github.com/e2eterraformprovider/terraform-provider-e2e/e2e/node"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/e2e/plan"
 	"github.com/e2eterraformprovider/terraform-provider-e2e/e2e/security_group"
 	"github.com/e2eterraformprovider/terraform-provider-e2e/e2e/ssh_key"
 	"github.com/e2eterraformprovider/terraform-provider-e2e/e2e/vpc"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func Provider() *schema.Provider {
@@ -35,19 +40,52 @@ func Provider() *schema.Provider {
 			},
 
 			"location": {
-				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("SERVICE_LOCATION", ""),
+				Type:         schema.TypeString,
+				Required:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("SERVICE_LOCATION", ""),
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location every request is sent to, one of Delhi or Mumbai. Resources supporting it can override this with their own location argument",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      client.DefaultMaxRetries,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of times a request is retried on rate limiting, 5xx responses and transient network errors. Requests that create or change objects are only retried when the API did not handle them (429, or 503 with Retry-After). Set to 0 to disable retries",
+			},
+			"max_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(client.DefaultMaxBackoff / time.Second),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum time in seconds to wait between retries, including waits requested through Retry-After",
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"e2e_node": node.ResourceNode(),
+			"e2e_node":                           node.ResourceNode(),
+			"e2e_node_power":                     node.ResourceNodePower(),
+			"e2e_node_reboot":                    node.ResourceNodeReboot(),
+			"e2e_node_reinstall":                 node.ResourceNodeReinstall(),
+			"e2e_node_security_group_attachment": node.ResourceNodeSecurityGroupAttachment(),
+			"e2e_image":                          image.ResourceImage(),
+			"e2e_ssh_key":                        ssh_key.ResourceSshKey(),
+			"e2e_security_group":                 security_group.ResourceSecurityGroup(),
+			"e2e_security_group_rule":            security_group.ResourceSecurityGroupRule(),
+			"e2e_vpc":                            vpc.ResourceVpc(),
+			"e2e_vpc_attachment":                 vpc.ResourceVpcAttachment(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"e2e_node":            node.DataSourceNode(),
+			"e2e_nodes":           node.DataSourceNodes(),
+			"e2e_image":           image.DataSourceImage(),
 			"e2e_images":          image.DataSourceImages(),
+			"e2e_os_images":       image.DataSourceOsImages(),
+			"e2e_plans":           plan.DataSourcePlans(),
+			"e2e_security_group":  security_group.DataSourceSecurityGroup(),
 			"e2e_security_groups": security_group.DataSourceSecurityGroups(),
+			"e2e_ssh_key":         ssh_key.DataSourceSshKey(),
 			"e2e_ssh_keys":        ssh_key.DataSourceSshKeys(),
+			"e2e_vpc":             vpc.DataSourceVpc(),
 			"e2e_vpcs":            vpc.DataSourceVpcs(),
 		},
 		ConfigureFunc: providerConfigure,
@@ -60,5 +98,9 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	auth_token := d.Get("auth_token").(string)
 
 	api_endpoint := d.Get("api_endpoint").(string)
-	return client.NewClient(api_key, auth_token, api_endpoint), nil
+	location := d.Get("location").(string)
+	apiClient := client.NewClient(api_key, auth_token, api_endpoint, location)
+	apiClient.Max_retries = d.Get("max_retries").(int)
+	apiClient.Max_backoff = time.Duration(d.Get("max_backoff").(int)) * time.Second
+	return apiClient, nil
 }
@@ -0,0 +1,118 @@
+package security_group
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func DataSourceSecurityGroup() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"name", "default"},
+				Description:  "name of the security group to look up",
+			},
+			"default": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				ValidateFunc: validateDefaultLookup,
+				Description:  "Set to true to look up the default security group of the account. false is not accepted, use name to look up another group",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location to look the security group up in. Defaults to the provider location",
+			},
+			"security_group_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Value to use as security_group_id of e2e_node",
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_default": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+
+		ReadContext: dataSourceReadSecurityGroup,
+	}
+}
+
+// validateDefaultLookup rejects default = false, which would otherwise match
+// every group but the default one.
+func validateDefaultLookup(v interface{}, k string) ([]string, []error) {
+	if !v.(bool) {
+		return nil, []error{fmt.Errorf("%s can only be set to true, use name to look up a security group that is not the default", k)}
+	}
+	return nil, nil
+}
+
+func dataSourceReadSecurityGroup(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+
+	log.Printf("[INFO] Inside security group data source ")
+	Response, err := apiClient.GetSecurityGroups(ctx)
+	if err != nil {
+		return diag.Errorf("error finding security groups: %s", err)
+	}
+
+	var description string
+	matched := []models.SecurityGroup{}
+	if v, ok := d.GetOk("name"); ok {
+		description = fmt.Sprintf("named %s", v.(string))
+		for _, securityGroup := range Response.Data {
+			if securityGroup.Name == v.(string) {
+				matched = append(matched, securityGroup)
+			}
+		}
+	} else {
+		description = "marked as default"
+		for _, securityGroup := range Response.Data {
+			if securityGroup.Is_default {
+				matched = append(matched, securityGroup)
+			}
+		}
+	}
+
+	if len(matched) == 0 {
+		return diag.Errorf("no security group %s found", description)
+	}
+	if len(matched) > 1 {
+		ids := make([]string, 0, len(matched))
+		for _, securityGroup := range matched {
+			ids = append(ids, strconv.Itoa(int(securityGroup.Id)))
+		}
+		return diag.Errorf("%d security groups %s found (ids %s)", len(matched), description, strings.Join(ids, ", "))
+	}
+
+	securityGroup := matched[0]
+	d.SetId(strconv.Itoa(int(securityGroup.Id)))
+	d.Set("location", apiClient.Location)
+	d.Set("name", securityGroup.Name)
+	d.Set("security_group_id", int(securityGroup.Id))
+	d.Set("description", securityGroup.Description)
+	d.Set("is_default", securityGroup.Is_default)
+
+	return diags
+}
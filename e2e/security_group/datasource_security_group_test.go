@@ -0,0 +1,82 @@
+package security_group_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceSecurityGroup_lookup(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_security_group" "web" {
+  name = "acc-web"
+}
+
+data "e2e_security_group" "default" {
+  default = true
+}
+
+data "e2e_security_group" "web" {
+  name = e2e_security_group.web.name
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_security_group.default", "security_group_id", "150"),
+					resource.TestCheckResourceAttr("data.e2e_security_group.default", "name", "default"),
+					resource.TestCheckResourceAttrPair("data.e2e_security_group.web", "id", "e2e_security_group.web", "id"),
+					resource.TestCheckResourceAttr("data.e2e_security_group.web", "is_default", "false"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_security_group" "missing" {
+  name = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`no security group named missing found`),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_security_group" "not_default" {
+  default = false
+}
+`,
+				ExpectError: regexp.MustCompile(`default can only be set to true`),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceSecurityGroup_location(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_security_group" "test" {
+  default  = true
+  location = "Mumbai"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_security_group.test", "location", "Mumbai"),
+					resource.TestCheckResourceAttr("data.e2e_security_group.test", "security_group_id", "150"),
+					acctest.CheckRequestLocation(server, "security_group/", "Mumbai"),
+				),
+			},
+		},
+	})
+}
@@ -123,7 +123,7 @@ func dataSourceReadSecurityGroups(ctx context.Context, d *schema.ResourceData, m
 	var diags diag.Diagnostics
 	apiClient := m.(*client.Client)
 	log.Printf("[INFO] Inside images data source ")
-	Response, err := apiClient.GetSecurityGroups()
+	Response, err := apiClient.GetSecurityGroups(ctx)
 	if err != nil {
 		return diag.Errorf("error finding security groups")
 	}
@@ -0,0 +1,30 @@
+package security_group_test
+
+import (
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceSecurityGroups_basic(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_security_groups" "test" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_security_groups.test", "security_group_list.#", "1"),
+					resource.TestCheckResourceAttr("data.e2e_security_groups.test", "security_group_list.0.is_default", "true"),
+					resource.TestCheckResourceAttr("data.e2e_security_groups.test", "security_group_list.0.rules.#", "1"),
+				),
+			},
+		},
+	})
+}
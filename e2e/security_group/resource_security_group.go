@@ -0,0 +1,298 @@
+package security_group
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	ruleTypeInbound  = "Inbound"
+	ruleTypeOutbound = "Outbound"
+)
+
+func ResourceSecurityGroup() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "name of the security group",
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			"is_default": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Makes this the default security group of the account. Only one security group can be the default",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location of the security group. Defaults to the provider location",
+			},
+			"inline_rules": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Manage the rules of the group with inbound_rule and outbound_rule. Any rule not given in them is removed, so leaving both out removes every rule. Leave false when using e2e_security_group_rule",
+			},
+			"inbound_rule": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Inbound rule blocks, requires inline_rules",
+				Elem:        ruleResource(),
+			},
+			"outbound_rule": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Outbound rule blocks, requires inline_rules",
+				Elem:        ruleResource(),
+			},
+		},
+
+		CreateContext: resourceCreateSecurityGroup,
+		ReadContext:   resourceReadSecurityGroup,
+		UpdateContext: resourceUpdateSecurityGroup,
+		DeleteContext: resourceDeleteSecurityGroup,
+		CustomizeDiff: customizeSecurityGroupDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportSecurityGroup,
+		},
+	}
+}
+
+func ruleResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"protocol_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Protocol of the rule, for example All, Custom_TCP, Custom_UDP or ICMP",
+			},
+			"port_range": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "All",
+				Description: "A port (22) or a range of ports (8000-8080)",
+			},
+			"network": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "any",
+				Description: "any to match every address, otherwise the network address the rule applies to",
+			},
+			"network_cidr": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "CIDR block the rule applies to when network is not any",
+			},
+		},
+	}
+}
+
+// The API replaces the whole rule set on every update, so concurrent changes
+// to the same security group (from several e2e_security_group_rule resources
+// for example) are serialized.
+var securityGroupLocks sync.Map
+
+func lockSecurityGroup(securityGroupId string) func() {
+	mutex, _ := securityGroupLocks.LoadOrStore(securityGroupId, &sync.Mutex{})
+	mutex.(*sync.Mutex).Lock()
+	return mutex.(*sync.Mutex).Unlock
+}
+
+func expandRules(rules *schema.Set, ruleType string) []models.AddRule {
+	addRules := make([]models.AddRule, 0, rules.Len())
+	for _, r := range rules.List() {
+		rule := r.(map[string]interface{})
+		addRules = append(addRules, models.AddRule{
+			Rule_type:     ruleType,
+			Protocol_name: rule["protocol_name"].(string),
+			Port_range:    rule["port_range"].(string),
+			Network:       rule["network"].(string),
+			Network_cidr:  rule["network_cidr"].(string),
+		})
+	}
+	return addRules
+}
+
+func flattenRules(rules []models.Rule, ruleType string) []interface{} {
+	rls := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Rule_type != ruleType {
+			continue
+		}
+		rls = append(rls, map[string]interface{}{
+			"protocol_name": rule.Protocol_name,
+			"port_range":    rule.Port_range,
+			"network":       rule.Network,
+			"network_cidr":  normalizeNetworkCidr(rule.Network_cidr),
+		})
+	}
+	return rls
+}
+
+// The API reports a missing CIDR as "--".
+func normalizeNetworkCidr(networkCidr string) string {
+	if networkCidr == "--" {
+		return ""
+	}
+	return networkCidr
+}
+
+func toAddRules(rules []models.Rule, ruleType string) []models.AddRule {
+	addRules := make([]models.AddRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Rule_type != ruleType {
+			continue
+		}
+		addRules = append(addRules, models.AddRule{
+			Rule_type:     rule.Rule_type,
+			Protocol_name: rule.Protocol_name,
+			Port_range:    rule.Port_range,
+			Network:       rule.Network,
+			Network_cidr:  normalizeNetworkCidr(rule.Network_cidr),
+		})
+	}
+	return addRules
+}
+
+// customizeSecurityGroupDiff rejects rule blocks when the rules are not
+// managed inline, they would otherwise be silently ignored.
+func customizeSecurityGroupDiff(ctx context.Context, diff *schema.ResourceDiff, m interface{}) error {
+	if !diff.NewValueKnown("inline_rules") || diff.Get("inline_rules").(bool) {
+		return nil
+	}
+	for _, key := range []string{"inbound_rule", "outbound_rule"} {
+		if !diff.NewValueKnown(key) || diff.Get(key).(*schema.Set).Len() > 0 {
+			return fmt.Errorf("%s requires inline_rules = true", key)
+		}
+	}
+	return nil
+}
+
+func resourceCreateSecurityGroup(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	d.Set("location", apiClient.Location)
+
+	log.Printf("[INFO] inside security group create")
+	securityGroup := models.AddSecurityGroup{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Default:     d.Get("is_default").(bool),
+		Rules:       expandRules(d.Get("inbound_rule").(*schema.Set), ruleTypeInbound),
+	}
+	securityGroup.Rules = append(securityGroup.Rules, expandRules(d.Get("outbound_rule").(*schema.Set), ruleTypeOutbound)...)
+
+	created, err := apiClient.CreateSecurityGroup(ctx, &securityGroup)
+	if err != nil {
+		return diag.Errorf("error creating security group %s: %s", securityGroup.Name, err)
+	}
+	d.SetId(strconv.Itoa(int(created.Id)))
+
+	return resourceReadSecurityGroup(ctx, d, m)
+}
+
+func resourceReadSecurityGroup(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+	d.Set("location", apiClient.Location)
+
+	securityGroup, err := apiClient.GetSecurityGroup(ctx, d.Id())
+	if err != nil {
+		if client.IsNotFound(err) {
+			log.Printf("[WARN] security group %s not found, removing from state", d.Id())
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error finding security group with ID %s: %s", d.Id(), err)
+	}
+
+	d.Set("name", securityGroup.Name)
+	d.Set("description", securityGroup.Description)
+	d.Set("is_default", securityGroup.Is_default)
+	// Rules managed with e2e_security_group_rule are left out of the state.
+	var rules []models.Rule
+	if d.Get("inline_rules").(bool) {
+		rules = securityGroup.Rules
+	}
+	if err := d.Set("inbound_rule", flattenRules(rules, ruleTypeInbound)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("outbound_rule", flattenRules(rules, ruleTypeOutbound)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func resourceUpdateSecurityGroup(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+
+	unlock := lockSecurityGroup(d.Id())
+	defer unlock()
+
+	current, err := apiClient.GetSecurityGroup(ctx, d.Id())
+	if err != nil {
+		return diag.Errorf("error finding security group with ID %s: %s", d.Id(), err)
+	}
+
+	securityGroup := models.AddSecurityGroup{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Default:     d.Get("is_default").(bool),
+	}
+	if d.Get("inline_rules").(bool) {
+		securityGroup.Rules = append(expandRules(d.Get("inbound_rule").(*schema.Set), ruleTypeInbound),
+			expandRules(d.Get("outbound_rule").(*schema.Set), ruleTypeOutbound)...)
+	} else {
+		securityGroup.Rules = append(toAddRules(current.Rules, ruleTypeInbound), toAddRules(current.Rules, ruleTypeOutbound)...)
+	}
+
+	_, err = apiClient.UpdateSecurityGroup(ctx, d.Id(), &securityGroup)
+	if err != nil {
+		return diag.Errorf("error updating security group %s: %s", d.Id(), err)
+	}
+
+	return resourceReadSecurityGroup(ctx, d, m)
+}
+
+func resourceDeleteSecurityGroup(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+
+	err := apiClient.DeleteSecurityGroup(ctx, d.Id())
+	if err != nil && !client.IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return diags
+}
+
+// resourceImportSecurityGroup imports the rules inline, a configuration using
+// e2e_security_group_rule instead only sees inline_rules change to false.
+func resourceImportSecurityGroup(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	d.Set("inline_rules", true)
+	return []*schema.ResourceData{d}, nil
+}
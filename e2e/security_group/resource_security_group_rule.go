@@ -0,0 +1,264 @@
+package security_group
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ResourceSecurityGroupRule manages a single rule of a security group, for
+// groups whose rules are spread across modules. It should not be combined
+// with inline_rules on e2e_security_group. Every change replaces the whole
+// rule set of the group, so a rule is identified by its content rather than
+// by the id the API gives it.
+func ResourceSecurityGroupRule() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"security_group_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "id of the security group the rule belongs to",
+			},
+			"rule_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{ruleTypeInbound, ruleTypeOutbound}, false),
+				Description:  "Inbound or Outbound",
+			},
+			"protocol_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Protocol of the rule, for example All, Custom_TCP, Custom_UDP or ICMP",
+			},
+			"port_range": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "All",
+				Description: "A port (22) or a range of ports (8000-8080)",
+			},
+			"network": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "any",
+				Description: "any to match every address, otherwise the network address the rule applies to",
+			},
+			"network_cidr": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "",
+				Description: "CIDR block the rule applies to when network is not any",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location of the security group. Defaults to the provider location",
+			},
+			"rule_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "id the API currently gives the rule, it may change whenever the rules of the group are saved",
+			},
+		},
+
+		CreateContext: resourceCreateSecurityGroupRule,
+		ReadContext:   resourceReadSecurityGroupRule,
+		DeleteContext: resourceDeleteSecurityGroupRule,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportSecurityGroupRule,
+		},
+	}
+}
+
+func ruleFromResourceData(d *schema.ResourceData) models.AddRule {
+	return models.AddRule{
+		Rule_type:     d.Get("rule_type").(string),
+		Protocol_name: d.Get("protocol_name").(string),
+		Port_range:    d.Get("port_range").(string),
+		Network:       d.Get("network").(string),
+		Network_cidr:  d.Get("network_cidr").(string),
+	}
+}
+
+func ruleMatches(rule models.Rule, addRule models.AddRule) bool {
+	return rule.Rule_type == addRule.Rule_type &&
+		rule.Protocol_name == addRule.Protocol_name &&
+		rule.Port_range == addRule.Port_range &&
+		rule.Network == addRule.Network &&
+		normalizeNetworkCidr(rule.Network_cidr) == addRule.Network_cidr
+}
+
+// securityGroupRuleId identifies a rule by its content. The network_cidr comes
+// last as it contains a / itself, and may be empty.
+func securityGroupRuleId(securityGroupId string, addRule models.AddRule) string {
+	return strings.Join([]string{securityGroupId, addRule.Rule_type, addRule.Protocol_name, addRule.Port_range, addRule.Network, addRule.Network_cidr}, "/")
+}
+
+func parseSecurityGroupRuleId(id string) (string, models.AddRule, error) {
+	parts := strings.SplitN(id, "/", 6)
+	valid := len(parts) == 6
+	if valid {
+		_, err := strconv.Atoi(parts[0])
+		valid = err == nil && parts[1] != "" && parts[2] != "" && parts[3] != "" && parts[4] != ""
+	}
+	if !valid {
+		return "", models.AddRule{}, fmt.Errorf("unexpected format of ID (%s), expected <security_group_id>/<rule_type>/<protocol_name>/<port_range>/<network>/<network_cidr>", id)
+	}
+	return parts[0], models.AddRule{
+		Rule_type:     parts[1],
+		Protocol_name: parts[2],
+		Port_range:    parts[3],
+		Network:       parts[4],
+		Network_cidr:  parts[5],
+	}, nil
+}
+
+func allRules(rules []models.Rule) []models.AddRule {
+	return append(toAddRules(rules, ruleTypeInbound), toAddRules(rules, ruleTypeOutbound)...)
+}
+
+func resourceCreateSecurityGroupRule(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	d.Set("location", apiClient.Location)
+
+	securityGroupId := strconv.Itoa(d.Get("security_group_id").(int))
+	unlock := lockSecurityGroup(securityGroupId)
+	defer unlock()
+
+	securityGroup, err := apiClient.GetSecurityGroup(ctx, securityGroupId)
+	if err != nil {
+		return diag.Errorf("error finding security group with ID %s: %s", securityGroupId, err)
+	}
+
+	addRule := ruleFromResourceData(d)
+	for _, rule := range securityGroup.Rules {
+		if ruleMatches(rule, addRule) {
+			return diag.Errorf("security group %s already has this %s rule. Import it as %s instead", securityGroupId, addRule.Rule_type, securityGroupRuleId(securityGroupId, addRule))
+		}
+	}
+
+	log.Printf("[INFO] adding %s rule to security group %s", addRule.Rule_type, securityGroupId)
+	updated, err := apiClient.UpdateSecurityGroup(ctx, securityGroupId, &models.AddSecurityGroup{
+		Name:        securityGroup.Name,
+		Description: securityGroup.Description,
+		Default:     securityGroup.Is_default,
+		Rules:       append(allRules(securityGroup.Rules), addRule),
+	})
+	if err != nil {
+		return diag.Errorf("error adding rule to security group %s: %s", securityGroupId, err)
+	}
+	for _, rule := range updated.Rules {
+		if ruleMatches(rule, addRule) {
+			d.SetId(securityGroupRuleId(securityGroupId, addRule))
+			return resourceReadSecurityGroupRule(ctx, d, m)
+		}
+	}
+	return diag.Errorf("rule was not found in security group %s after adding it", securityGroupId)
+}
+
+func resourceReadSecurityGroupRule(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+	d.Set("location", apiClient.Location)
+
+	securityGroupId, addRule, err := parseSecurityGroupRuleId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	securityGroup, err := apiClient.GetSecurityGroup(ctx, securityGroupId)
+	if err != nil {
+		if client.IsNotFound(err) {
+			log.Printf("[WARN] security group %s not found, removing rule from state", securityGroupId)
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error finding security group with ID %s: %s", securityGroupId, err)
+	}
+
+	for _, rule := range securityGroup.Rules {
+		if !ruleMatches(rule, addRule) {
+			continue
+		}
+		securityGroupIdInt, _ := strconv.Atoi(securityGroupId)
+		d.Set("security_group_id", securityGroupIdInt)
+		d.Set("rule_type", rule.Rule_type)
+		d.Set("protocol_name", rule.Protocol_name)
+		d.Set("port_range", rule.Port_range)
+		d.Set("network", rule.Network)
+		d.Set("network_cidr", normalizeNetworkCidr(rule.Network_cidr))
+		d.Set("rule_id", int(rule.Id))
+		return diags
+	}
+
+	log.Printf("[WARN] rule %s not found in security group %s, removing from state", d.Id(), securityGroupId)
+	d.SetId("")
+	return diags
+}
+
+func resourceDeleteSecurityGroupRule(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+
+	securityGroupId, addRule, err := parseSecurityGroupRuleId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	unlock := lockSecurityGroup(securityGroupId)
+	defer unlock()
+
+	securityGroup, err := apiClient.GetSecurityGroup(ctx, securityGroupId)
+	if err != nil {
+		if client.IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error finding security group with ID %s: %s", securityGroupId, err)
+	}
+
+	remaining := make([]models.Rule, 0, len(securityGroup.Rules))
+	for _, rule := range securityGroup.Rules {
+		if !ruleMatches(rule, addRule) {
+			remaining = append(remaining, rule)
+		}
+	}
+	if len(remaining) < len(securityGroup.Rules) {
+		log.Printf("[INFO] removing rule %s from security group %s", d.Id(), securityGroupId)
+		_, err = apiClient.UpdateSecurityGroup(ctx, securityGroupId, &models.AddSecurityGroup{
+			Name:        securityGroup.Name,
+			Description: securityGroup.Description,
+			Default:     securityGroup.Is_default,
+			Rules:       allRules(remaining),
+		})
+		if err != nil {
+			return diag.Errorf("error removing rule %s from security group %s: %s", d.Id(), securityGroupId, err)
+		}
+	}
+	d.SetId("")
+	return diags
+}
+
+func resourceImportSecurityGroupRule(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseSecurityGroupRuleId(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,91 @@
+package security_group_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSecurityGroupRule_basic(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccSecurityGroupRuleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("e2e_security_group_rule.ssh", "rule_id"),
+					resource.TestCheckResourceAttrSet("e2e_security_group_rule.egress", "rule_id"),
+					resource.TestMatchResourceAttr("e2e_security_group_rule.ssh", "id", regexp.MustCompile(`^\d+/Inbound/Custom_TCP/22/any/$`)),
+					resource.TestMatchResourceAttr("e2e_security_group_rule.egress", "id", regexp.MustCompile(`^\d+/Outbound/All/All/any/$`)),
+				),
+			},
+			{
+				ResourceName:      "e2e_security_group_rule.ssh",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// Adding a rule saves the whole rule set again, which gives
+				// every rule a new id. The existing rules must be kept.
+				Config: acctest.ProviderConfig(server) + testAccSecurityGroupRuleConfig + `
+resource "e2e_security_group_rule" "office" {
+  security_group_id = e2e_security_group.test.id
+  rule_type         = "Inbound"
+  protocol_name     = "Custom_TCP"
+  port_range        = "443"
+  network           = "10.20.0.0"
+  network_cidr      = "10.20.0.0/16"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("e2e_security_group_rule.office", "id", regexp.MustCompile(`^\d+/Inbound/Custom_TCP/443/10.20.0.0/10.20.0.0/16$`)),
+					resource.TestCheckResourceAttr("e2e_security_group_rule.ssh", "port_range", "22"),
+					resource.TestCheckResourceAttr("e2e_security_group_rule.egress", "rule_type", "Outbound"),
+				),
+			},
+			{
+				ResourceName:      "e2e_security_group_rule.office",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccSecurityGroupRuleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_security_group_rule.ssh", "port_range", "22"),
+					resource.TestCheckResourceAttr("e2e_security_group_rule.egress", "rule_type", "Outbound"),
+				),
+			},
+			{
+				ResourceName:  "e2e_security_group_rule.ssh",
+				ImportState:   true,
+				ImportStateId: "1001/22",
+				ExpectError:   regexp.MustCompile(`unexpected format of ID \(1001/22\)`),
+			},
+		},
+	})
+}
+
+const testAccSecurityGroupRuleConfig = `
+resource "e2e_security_group" "test" {
+  name = "shared"
+}
+
+resource "e2e_security_group_rule" "ssh" {
+  security_group_id = e2e_security_group.test.id
+  rule_type         = "Inbound"
+  protocol_name     = "Custom_TCP"
+  port_range        = "22"
+}
+
+resource "e2e_security_group_rule" "egress" {
+  security_group_id = e2e_security_group.test.id
+  rule_type         = "Outbound"
+  protocol_name     = "All"
+}
+`
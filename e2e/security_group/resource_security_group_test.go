@@ -0,0 +1,195 @@
+package security_group_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccSecurityGroup_inlineRules(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_security_group" "test" {
+  name         = "web"
+  description  = "web servers"
+  inline_rules = true
+
+  inbound_rule {
+    protocol_name = "Custom_TCP"
+    port_range    = "22"
+    network       = "10.0.0.0"
+    network_cidr  = "10.0.0.0/16"
+  }
+  inbound_rule {
+    protocol_name = "Custom_TCP"
+    port_range    = "443"
+  }
+  outbound_rule {
+    protocol_name = "All"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_security_group.test", "name", "web"),
+					resource.TestCheckResourceAttr("e2e_security_group.test", "location", "Delhi"),
+					resource.TestCheckResourceAttr("e2e_security_group.test", "inbound_rule.#", "2"),
+					resource.TestCheckResourceAttr("e2e_security_group.test", "outbound_rule.#", "1"),
+				),
+			},
+			{
+				// The same rules in another order must not produce a diff.
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_security_group" "test" {
+  name         = "web"
+  description  = "web servers"
+  inline_rules = true
+
+  outbound_rule {
+    protocol_name = "All"
+  }
+  inbound_rule {
+    protocol_name = "Custom_TCP"
+    port_range    = "443"
+  }
+  inbound_rule {
+    protocol_name = "Custom_TCP"
+    port_range    = "22"
+    network       = "10.0.0.0"
+    network_cidr  = "10.0.0.0/16"
+  }
+}
+`,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "e2e_security_group.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_security_group" "test" {
+  name         = "web"
+  description  = "web servers"
+  inline_rules = true
+
+  inbound_rule {
+    protocol_name = "Custom_TCP"
+    port_range    = "443"
+  }
+  outbound_rule {
+    protocol_name = "All"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_security_group.test", "inbound_rule.#", "1"),
+					testAccCheckSecurityGroupRules(server, "e2e_security_group.test", "Inbound", "443"),
+				),
+			},
+			{
+				// Removing the last rule of a direction removes it from the
+				// group as well.
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_security_group" "test" {
+  name         = "web"
+  description  = "web servers"
+  inline_rules = true
+
+  outbound_rule {
+    protocol_name = "All"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_security_group.test", "inbound_rule.#", "0"),
+					testAccCheckSecurityGroupRules(server, "e2e_security_group.test", "Inbound"),
+					testAccCheckSecurityGroupRules(server, "e2e_security_group.test", "Outbound", "All"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_security_group" "test" {
+  name        = "web"
+  description = "web servers"
+
+  outbound_rule {
+    protocol_name = "All"
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`outbound_rule requires inline_rules = true`),
+			},
+		},
+	})
+}
+
+func TestAccSecurityGroup_location(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_security_group" "test" {
+  name     = "web"
+  location = "Mumbai"
+}
+
+resource "e2e_security_group_rule" "ssh" {
+  security_group_id = e2e_security_group.test.id
+  rule_type         = "Inbound"
+  protocol_name     = "Custom_TCP"
+  port_range        = "22"
+  location          = e2e_security_group.test.location
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_security_group.test", "location", "Mumbai"),
+					resource.TestCheckResourceAttr("e2e_security_group_rule.ssh", "location", "Mumbai"),
+					acctest.CheckRequestLocation(server, "security_group/", "Mumbai"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckSecurityGroupRules checks the port ranges of the rules of the
+// given type the fake API holds for the group.
+func testAccCheckSecurityGroupRules(server *fakeapi.Server, name string, ruleType string, portRanges ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		id, err := strconv.Atoi(s.RootModule().Resources[name].Primary.ID)
+		if err != nil {
+			return err
+		}
+		group, ok := server.SecurityGroup(id)
+		if !ok {
+			return fmt.Errorf("security group %d not found", id)
+		}
+		got := []string{}
+		for _, rule := range group.Rules {
+			if rule.Rule_type == ruleType {
+				got = append(got, rule.Port_range)
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(portRanges) {
+			return fmt.Errorf("expected %s rules with port ranges %v on security group %d, got %v", ruleType, portRanges, id, got)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,91 @@
+package ssh_key
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func DataSourceSshKey() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"label": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "label of the ssh key to look up",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location to look the ssh key up in. Defaults to the provider location",
+			},
+			"ssh_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The public key. Use this value in the ssh_keys field of e2e_node",
+			},
+			"pk": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "id of the ssh key",
+			},
+			"timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		ReadContext: dataSourceReadSshKey,
+	}
+}
+
+func dataSourceReadSshKey(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+
+	log.Printf("[INFO] Inside ssh key data source ")
+	Response, err := apiClient.GetSshKeys(ctx)
+	if err != nil {
+		return diag.Errorf("error finding ssh keys: %s", err)
+	}
+
+	label := d.Get("label").(string)
+	matched := []models.SshKey{}
+	for _, sshKey := range Response.Data {
+		if sshKey.Label == label {
+			matched = append(matched, sshKey)
+		}
+	}
+
+	if len(matched) == 0 {
+		return diag.Errorf("no ssh key labelled %s found", label)
+	}
+	if len(matched) > 1 {
+		ids := make([]string, 0, len(matched))
+		for _, sshKey := range matched {
+			ids = append(ids, strconv.Itoa(sshKey.Pk))
+		}
+		return diag.Errorf("%d ssh keys labelled %s found (ids %s)", len(matched), label, strings.Join(ids, ", "))
+	}
+
+	sshKey := matched[0]
+	d.SetId(strconv.Itoa(sshKey.Pk))
+	d.Set("location", apiClient.Location)
+	d.Set("ssh_key", sshKey.Ssh_key)
+	d.Set("pk", sshKey.Pk)
+	d.Set("timestamp", sshKey.Timestamp)
+
+	return diags
+}
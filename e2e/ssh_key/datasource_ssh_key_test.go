@@ -0,0 +1,64 @@
+package ssh_key_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceSshKey_lookup(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_ssh_key" "ops" {
+  label = "ops"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_ssh_key.ops", "pk", "11"),
+					resource.TestCheckResourceAttr("data.e2e_ssh_key.ops", "ssh_key", "ssh-rsa AAAAB3NzaC1yc2E ops@example.com"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_ssh_key" "missing" {
+  label = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`no ssh key labelled missing found`),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceSshKey_location(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_ssh_key" "test" {
+  label    = "ops"
+  location = "Mumbai"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_ssh_key.test", "location", "Mumbai"),
+					resource.TestCheckResourceAttr("data.e2e_ssh_key.test", "pk", "11"),
+					acctest.CheckRequestLocation(server, "ssh_keys/", "Mumbai"),
+				),
+			},
+		},
+	})
+}
@@ -61,7 +61,7 @@ func dataSourceReadSshKeys(ctx context.Context, d *schema.ResourceData, m interf
 	var diags diag.Diagnostics
 	apiClient := m.(*client.Client)
 	log.Printf("[INFO] Inside sshkeys data source ")
-	Response, err := apiClient.GetSshKeys()
+	Response, err := apiClient.GetSshKeys(ctx)
 	if err != nil {
 		return diag.Errorf("error finding ssh keys")
 	}
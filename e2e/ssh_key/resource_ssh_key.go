@@ -0,0 +1,123 @@
+package ssh_key
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func ResourceSshKey() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"label": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Label of the ssh key, must be unique in the account",
+			},
+			"ssh_key": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringMatch(sshKeyPattern, "expected an OpenSSH public key"),
+				DiffSuppressFunc: suppressSshKeyWhitespace,
+				Description:      "The public key, in OpenSSH format. Use this value in the ssh_keys field of e2e_node",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location of the ssh key. Defaults to the provider location",
+			},
+			"pk": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "id of the ssh key",
+			},
+			"timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		CreateContext: resourceCreateSshKey,
+		ReadContext:   resourceReadSshKey,
+		DeleteContext: resourceDeleteSshKey,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+	}
+}
+
+var sshKeyPattern = regexp.MustCompile(`^\s*(ssh-(rsa|dss|ed25519)|ecdsa-sha2-nistp(256|384|521)|sk-\S+@openssh\.com)\s+\S+`)
+
+// Keys pasted from a file usually carry a trailing newline.
+func suppressSshKeyWhitespace(k, old, new string, d *schema.ResourceData) bool {
+	return strings.TrimSpace(old) == strings.TrimSpace(new)
+}
+
+func resourceCreateSshKey(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	d.Set("location", apiClient.Location)
+
+	log.Printf("[INFO] inside ssh key create")
+	sshKey := models.AddSshKey{
+		Label:   d.Get("label").(string),
+		Ssh_key: strings.TrimSpace(d.Get("ssh_key").(string)),
+	}
+	created, err := apiClient.CreateSshKey(ctx, &sshKey)
+	if err != nil {
+		return diag.Errorf("error creating ssh key %s: %s", sshKey.Label, err)
+	}
+	d.SetId(strconv.Itoa(created.Pk))
+
+	return resourceReadSshKey(ctx, d, m)
+}
+
+func resourceReadSshKey(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+	d.Set("location", apiClient.Location)
+
+	sshKey, err := apiClient.GetSshKey(ctx, d.Id())
+	if err != nil {
+		if client.IsNotFound(err) {
+			log.Printf("[WARN] ssh key %s not found, removing from state", d.Id())
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error finding ssh key with ID %s: %s", d.Id(), err)
+	}
+
+	d.Set("label", sshKey.Label)
+	d.Set("ssh_key", sshKey.Ssh_key)
+	d.Set("pk", sshKey.Pk)
+	d.Set("timestamp", sshKey.Timestamp)
+
+	return diags
+}
+
+func resourceDeleteSshKey(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+
+	err := apiClient.DeleteSshKey(ctx, d.Id())
+	if err != nil && !client.IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return diags
+}
@@ -0,0 +1,97 @@
+package ssh_key_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccSshKey_basic(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckSshKeyDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_ssh_key" "test" {
+  label   = "onboarding"
+  ssh_key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5 dev@example.com"
+}
+
+resource "e2e_node" "test" {
+  name     = "acc-node"
+  label    = "acc"
+  plan     = "C2.40GB"
+  image    = "Ubuntu-22.04-Distro"
+  ssh_keys = [e2e_ssh_key.test.ssh_key]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_ssh_key.test", "label", "onboarding"),
+					resource.TestCheckResourceAttrSet("e2e_ssh_key.test", "pk"),
+					resource.TestCheckResourceAttrSet("e2e_ssh_key.test", "timestamp"),
+					resource.TestCheckResourceAttr("e2e_ssh_key.test", "location", "Delhi"),
+					resource.TestCheckResourceAttrPair("e2e_node.test", "ssh_keys.0", "e2e_ssh_key.test", "ssh_key"),
+				),
+			},
+			{
+				ResourceName:      "e2e_ssh_key.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccSshKey_location(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		CheckDestroy:      testAccCheckSshKeyDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_ssh_key" "test" {
+  label    = "onboarding"
+  ssh_key  = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5 dev@example.com"
+  location = "Mumbai"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_ssh_key.test", "location", "Mumbai"),
+					acctest.CheckRequestLocation(server, "ssh_keys/", "Mumbai"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckSshKeyDestroy(server *fakeapi.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		apiClient := client.NewClient(fakeapi.APIKey, fakeapi.AuthToken, server.Endpoint(), "Delhi")
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "e2e_ssh_key" {
+				continue
+			}
+			_, err := apiClient.GetSshKey(context.Background(), rs.Primary.ID)
+			if err == nil {
+				return fmt.Errorf("ssh key %s still exists", rs.Primary.ID)
+			}
+			if !client.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package vpc
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func DataSourceVpc() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "name of the vpc to look up",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location to look the vpc up in. Defaults to the provider location",
+			},
+			"network_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "id of the vpc. Use this value as vpc_id of e2e_node",
+			},
+			"ipv4_cidr": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"gateway_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"pool_size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_active": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		ReadContext: dataSourceReadVpc,
+	}
+}
+
+func dataSourceReadVpc(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+
+	log.Printf("[INFO] Inside vpc data source ")
+	Response, err := apiClient.GetVpcs(ctx)
+	if err != nil {
+		return diag.Errorf("error finding vpcs: %s", err)
+	}
+
+	name := d.Get("name").(string)
+	matched := []models.Vpc{}
+	for _, vpc := range Response.Data {
+		if vpc.Name == name {
+			matched = append(matched, vpc)
+		}
+	}
+
+	if len(matched) == 0 {
+		return diag.Errorf("no vpc named %s found", name)
+	}
+	if len(matched) > 1 {
+		ids := make([]string, 0, len(matched))
+		for _, vpc := range matched {
+			ids = append(ids, strconv.Itoa(int(vpc.Network_id)))
+		}
+		return diag.Errorf("%d vpcs named %s found (ids %s)", len(matched), name, strings.Join(ids, ", "))
+	}
+
+	vpc := matched[0]
+	d.SetId(strconv.Itoa(int(vpc.Network_id)))
+	d.Set("location", apiClient.Location)
+	d.Set("network_id", int(vpc.Network_id))
+	d.Set("ipv4_cidr", vpc.Ipv4_cidr)
+	d.Set("gateway_ip", vpc.Gateway_ip)
+	d.Set("pool_size", int(vpc.Pool_size))
+	d.Set("state", vpc.State)
+	d.Set("is_active", vpc.Is_active)
+	d.Set("created_at", vpc.Created_at)
+
+	return diags
+}
@@ -0,0 +1,83 @@
+package vpc_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceVpc_lookup(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_vpc" "default" {
+  name = "default-vpc"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_vpc.default", "network_id", "301"),
+					resource.TestCheckResourceAttr("data.e2e_vpc.default", "ipv4_cidr", "10.10.0.0/23"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_vpc" "one" {
+  name = "dup"
+}
+
+resource "e2e_vpc" "two" {
+  name = "dup"
+}
+`,
+			},
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_vpc" "one" {
+  name = "dup"
+}
+
+resource "e2e_vpc" "two" {
+  name = "dup"
+}
+
+data "e2e_vpc" "dup" {
+  name = "dup"
+}
+`,
+				ExpectError: regexp.MustCompile(`2 vpcs named dup found`),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceVpc_location(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+data "e2e_vpc" "test" {
+  name     = "default-vpc"
+  location = "Mumbai"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.e2e_vpc.test", "location", "Mumbai"),
+					resource.TestCheckResourceAttr("data.e2e_vpc.test", "network_id", "301"),
+					acctest.CheckRequestLocation(server, "vpc/", "Mumbai"),
+				),
+			},
+		},
+	})
+}
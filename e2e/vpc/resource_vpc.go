@@ -0,0 +1,185 @@
+package vpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func ResourceVpc() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "name of the vpc",
+			},
+			"ipv4_cidr": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsCIDR,
+				Description:  "IPv4 range of the vpc. Only honored in locations supporting custom ranges, otherwise one is assigned",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location of the vpc. Defaults to the provider location",
+			},
+			"network_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"gateway_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"pool_size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_active": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		CreateContext: resourceCreateVpc,
+		ReadContext:   resourceReadVpc,
+		DeleteContext: resourceDeleteVpc,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		},
+	}
+}
+
+func resourceCreateVpc(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	d.Set("location", apiClient.Location)
+
+	log.Printf("[INFO] inside vpc create")
+	vpc := models.AddVpc{
+		Name: d.Get("name").(string),
+		Ipv4: d.Get("ipv4_cidr").(string),
+	}
+	created, err := apiClient.CreateVpc(ctx, &vpc)
+	if err != nil {
+		return diag.Errorf("error creating vpc %s: %s", vpc.Name, err)
+	}
+	d.SetId(strconv.Itoa(int(created.Network_id)))
+
+	if err := waitForVpcActive(ctx, apiClient, d.Id(), d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceReadVpc(ctx, d, m)
+}
+
+func waitForVpcActive(ctx context.Context, apiClient *client.Client, networkId string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"Creating", "Pending"},
+		Target:  []string{"Active"},
+		Refresh: func() (interface{}, string, error) {
+			vpc, err := apiClient.GetVpc(ctx, networkId)
+			if err != nil {
+				return nil, "", err
+			}
+			log.Printf("[INFO] vpc %s state %s", networkId, vpc.State)
+			return vpc, vpc.State, nil
+		},
+		Timeout:    timeout,
+		Delay:      5 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		if timeoutErr, ok := err.(*resource.TimeoutError); ok {
+			return fmt.Errorf("timed out after %s waiting for vpc %s to become Active, last state: %s", timeout, networkId, timeoutErr.LastState)
+		}
+		return fmt.Errorf("error waiting for vpc %s to become Active: %s", networkId, err)
+	}
+	return nil
+}
+
+func resourceReadVpc(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+	d.Set("location", apiClient.Location)
+
+	vpc, err := apiClient.GetVpc(ctx, d.Id())
+	if err != nil {
+		if client.IsNotFound(err) {
+			log.Printf("[WARN] vpc %s not found, removing from state", d.Id())
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error finding vpc with ID %s: %s", d.Id(), err)
+	}
+
+	d.Set("name", vpc.Name)
+	d.Set("ipv4_cidr", vpc.Ipv4_cidr)
+	d.Set("network_id", int(vpc.Network_id))
+	d.Set("gateway_ip", vpc.Gateway_ip)
+	d.Set("pool_size", int(vpc.Pool_size))
+	d.Set("state", vpc.State)
+	d.Set("is_active", vpc.Is_active)
+	d.Set("created_at", vpc.Created_at)
+
+	return diags
+}
+
+func resourceDeleteVpc(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+
+	vpc, err := apiClient.GetVpc(ctx, d.Id())
+	if err != nil {
+		if client.IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error finding vpc with ID %s: %s", d.Id(), err)
+	}
+	if vpc.Vm_count > 0 {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("vpc %s still has %d node(s) attached", vpc.Name, vpc.Vm_count),
+			Detail:   "Detach or destroy the nodes using this vpc before deleting it. If the nodes are managed in this configuration, make sure they reference the vpc (for example vpc_id = e2e_vpc.<name>.network_id, or an e2e_vpc_attachment) so they are destroyed or detached first.",
+		}}
+	}
+
+	err = apiClient.DeleteVpc(ctx, d.Id())
+	if err != nil && !client.IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return diags
+}
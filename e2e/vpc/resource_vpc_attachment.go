@@ -0,0 +1,172 @@
+package vpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var numericIdPattern = regexp.MustCompile(`^[0-9]+$`)
+
+func ResourceVpcAttachment() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"node_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(numericIdPattern, "expected a numeric node id"),
+				Description:  "id of the node to attach to the vpc",
+			},
+			"vpc_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(numericIdPattern, "expected a numeric network id"),
+				Description:  "network id of the vpc. Do not also set it as vpc_id of the e2e_node, otherwise the two undo each other",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(constants.Locations, false),
+				Description:  "Location of the node. Defaults to the provider location",
+			},
+			"private_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "private IP of the node in the vpc",
+			},
+		},
+
+		CreateContext: resourceCreateVpcAttachment,
+		ReadContext:   resourceReadVpcAttachment,
+		DeleteContext: resourceDeleteVpcAttachment,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportVpcAttachment,
+		},
+	}
+}
+
+func parseVpcAttachmentId(id string) (string, string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 || !numericIdPattern.MatchString(parts[0]) || !numericIdPattern.MatchString(parts[1]) {
+		return "", "", fmt.Errorf("unexpected format of ID (%s), expected <node_id>/<vpc_id>", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// findNodeVpc returns the vpc of the node with the given network id, nil
+// when the node is not attached to it.
+func findNodeVpc(node *models.NodeDetail, vpcId string) *models.NodeVpc {
+	for _, vpc := range node.Vpcs {
+		if strconv.Itoa(vpc.Network_id) == vpcId {
+			return &vpc
+		}
+	}
+	return nil
+}
+
+func resourceCreateVpcAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	d.Set("location", apiClient.Location)
+
+	log.Printf("[INFO] inside vpc attachment create")
+	nodeId := d.Get("node_id").(string)
+	vpcId := d.Get("vpc_id").(string)
+
+	node, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		return diag.Errorf("error finding node with ID %s: %s", nodeId, err)
+	}
+	if node.Is_locked {
+		return diag.Errorf("cannot attach node %s to vpc %s as the node is locked", nodeId, vpcId)
+	}
+	if findNodeVpc(node, vpcId) != nil {
+		return diag.Errorf("node %s is already attached to vpc %s, import it with the ID %s/%s to manage it", nodeId, vpcId, nodeId, vpcId)
+	}
+
+	networkId, _ := strconv.Atoi(vpcId)
+	if err := apiClient.AttachVpc(ctx, node.Id, networkId); err != nil {
+		return diag.Errorf("error attaching node %s to vpc %s: %s", nodeId, vpcId, err)
+	}
+	d.SetId(fmt.Sprintf("%s/%s", nodeId, vpcId))
+
+	return resourceReadVpcAttachment(ctx, d, m)
+}
+
+func resourceReadVpcAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+	d.Set("location", apiClient.Location)
+
+	nodeId, vpcId, err := parseVpcAttachmentId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	node, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		if client.IsNotFound(err) {
+			log.Printf("[WARN] node %s not found, removing vpc attachment from state", nodeId)
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error finding node with ID %s: %s", nodeId, err)
+	}
+	vpc := findNodeVpc(node, vpcId)
+	if vpc == nil {
+		log.Printf("[WARN] node %s no longer attached to vpc %s, removing from state", nodeId, vpcId)
+		d.SetId("")
+		return diags
+	}
+
+	d.Set("node_id", nodeId)
+	d.Set("vpc_id", vpcId)
+	d.Set("private_ip", vpc.Private_ip)
+	return diags
+}
+
+func resourceDeleteVpcAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*client.Client).ForLocation(d.Get("location").(string))
+	var diags diag.Diagnostics
+
+	nodeId, vpcId, err := parseVpcAttachmentId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	node, err := apiClient.GetNode(ctx, nodeId)
+	if err != nil {
+		if client.IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error finding node with ID %s: %s", nodeId, err)
+	}
+	if findNodeVpc(node, vpcId) != nil {
+		networkId, _ := strconv.Atoi(vpcId)
+		if err := apiClient.DetachVpc(ctx, node.Id, networkId); err != nil {
+			return diag.Errorf("error detaching node %s from vpc %s: %s", nodeId, vpcId, err)
+		}
+	}
+	d.SetId("")
+	return diags
+}
+
+func resourceImportVpcAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseVpcAttachmentId(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
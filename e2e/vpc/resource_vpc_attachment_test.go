@@ -0,0 +1,86 @@
+package vpc_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccVpcAttachment_basic(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + testAccVpcAttachmentConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("e2e_vpc_attachment.test", "private_ip", regexp.MustCompile(`^10\.30\.0\.\d+$`)),
+					resource.TestCheckResourceAttr("e2e_vpc_attachment.test", "location", "Delhi"),
+					testAccCheckNodeVpcCount(server, "e2e_node.test", 1),
+				),
+			},
+			{
+				ResourceName:      "e2e_vpc_attachment.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: acctest.ProviderConfig(server) + testAccVpcAttachmentConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNodeVpcCount(server, "e2e_node.test", 0),
+					resource.TestCheckResourceAttr("e2e_node.test", "vpc_private_ips.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccVpcAttachmentConfig(attached bool) string {
+	config := `
+resource "e2e_vpc" "test" {
+  name      = "acc-vpc"
+  ipv4_cidr = "10.30.0.0/23"
+}
+
+resource "e2e_node" "test" {
+  name  = "acc-node"
+  label = "acc"
+  plan  = "C2.40GB"
+  image = "Ubuntu-22.04-Distro"
+}
+`
+	if attached {
+		config += `
+resource "e2e_vpc_attachment" "test" {
+  node_id = e2e_node.test.id
+  vpc_id  = e2e_vpc.test.id
+}
+`
+	}
+	return config
+}
+
+func testAccCheckNodeVpcCount(server *fakeapi.Server, name string, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		id, err := strconv.Atoi(s.RootModule().Resources[name].Primary.ID)
+		if err != nil {
+			return err
+		}
+		node, ok := server.Node(id)
+		if !ok {
+			return fmt.Errorf("node %d not found", id)
+		}
+		if len(node.Vpcs) != count {
+			return fmt.Errorf("expected node %d to be attached to %d vpc(s), got %+v", id, count, node.Vpcs)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,116 @@
+package vpc_test
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/client"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/acctest"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccVpc_basic(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_vpc" "test" {
+  name      = "acc-vpc"
+  ipv4_cidr = "10.20.0.0/23"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_vpc.test", "state", "Active"),
+					resource.TestCheckResourceAttr("e2e_vpc.test", "gateway_ip", "10.20.0.1"),
+					resource.TestCheckResourceAttr("e2e_vpc.test", "location", "Delhi"),
+					resource.TestCheckResourceAttrSet("e2e_vpc.test", "network_id"),
+				),
+			},
+			{
+				ResourceName:      "e2e_vpc.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccVpc_location(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(server) + `
+resource "e2e_vpc" "test" {
+  name     = "acc-vpc"
+  location = "Mumbai"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("e2e_vpc.test", "location", "Mumbai"),
+					acctest.CheckRequestLocation(server, "vpc/", "Mumbai"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccVpc_deleteWithNodes(t *testing.T) {
+	server := fakeapi.New()
+	defer server.Close()
+	apiClient := client.NewClient(fakeapi.APIKey, fakeapi.AuthToken, server.Endpoint(), "Delhi")
+	var nodeId string
+
+	config := acctest.ProviderConfig(server) + `
+resource "e2e_vpc" "test" {
+  name = "acc-vpc"
+}
+`
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: acctest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: func(s *terraform.State) error {
+					// Launch a node in the vpc outside of Terraform.
+					created, err := apiClient.NewNode(context.Background(), &models.Node{
+						Name:   "console-node",
+						Plan:   "C2.40GB",
+						Image:  "Ubuntu-22.04-Distro",
+						Vpc_id: s.RootModule().Resources["e2e_vpc.test"].Primary.ID,
+					})
+					if err != nil {
+						return err
+					}
+					nodeId = strconv.Itoa(created.Data.Id)
+					return nil
+				},
+			},
+			{
+				Config:      config,
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`still has 1 node\(s\) attached`),
+			},
+			{
+				PreConfig: func() {
+					if err := apiClient.DeleteNode(context.Background(), nodeId); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+			},
+		},
+	})
+}
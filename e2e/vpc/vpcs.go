@@ -77,7 +77,7 @@ func dataSourceReadVpcs(ctx context.Context, d *schema.ResourceData, m interface
 	var diags diag.Diagnostics
 	apiClient := m.(*client.Client)
 	log.Printf("[INFO] Inside vpcs data source ")
-	Response, err := apiClient.GetVpcs()
+	Response, err := apiClient.GetVpcs(ctx)
 	if err != nil {
 		return diag.Errorf("error finding vpcs ")
 	}
@@ -0,0 +1,64 @@
+// Package acctest wires the provider to an in-process fakeapi server for
+// acceptance tests.
+//
+// The tests only run with TF_ACC=1 and need a Terraform CLI. Set
+// TF_ACC_TERRAFORM_PATH to a local binary, otherwise terraform-plugin-sdk
+// downloads the latest release and the tests are not offline. `make testacc`
+// fetches the pinned TERRAFORM_VERSION (1.5.7) once into bin/ and runs them
+// with it.
+package acctest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/e2e"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// ProviderFactories returns the factories to use in resource.TestCase.
+func ProviderFactories() map[string]func() (*schema.Provider, error) {
+	return map[string]func() (*schema.Provider, error){
+		"e2e": func() (*schema.Provider, error) {
+			return e2e.Provider(), nil
+		},
+	}
+}
+
+// ProviderConfig returns a provider block pointing at server. Prepend it to
+// the configuration of every test step.
+func ProviderConfig(server *fakeapi.Server) string {
+	return fmt.Sprintf(`
+provider "e2e" {
+  api_key      = %q
+  auth_token   = %q
+  api_endpoint = %q
+  location     = "Delhi"
+  max_backoff  = 1
+}
+`, fakeapi.APIKey, fakeapi.AuthToken, server.Endpoint())
+}
+
+// CheckRequestLocation checks that the fake API received requests for path,
+// relative to the endpoint, and that all of them were sent to location.
+func CheckRequestLocation(server *fakeapi.Server, path string, location string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		count := 0
+		for _, r := range server.Requests() {
+			if !strings.HasPrefix(strings.TrimPrefix(r.URL.Path, "/myaccount/api/v1/"), path) {
+				continue
+			}
+			count++
+			if got := r.URL.Query().Get("location"); got != location {
+				return fmt.Errorf("expected %s %s to be sent to %s, got %s", r.Method, r.URL.Path, location, got)
+			}
+		}
+		if count == 0 {
+			return fmt.Errorf("no request for %s received", path)
+		}
+		return nil
+	}
+}
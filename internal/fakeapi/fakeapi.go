@@ -0,0 +1,620 @@
+// Package fakeapi is an in-process fake of the E2E MyAccount API. It serves
+// the endpoints used by the client package so acceptance tests can run
+// offline. State is kept in memory and nodes move through the same status
+// transitions as the real API, one step per poll.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/constants"
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+)
+
+const (
+	APIKey    = "fake-api-key"
+	AuthToken = "fake-auth-token"
+)
+
+type Node struct {
+	models.NodeDetail
+
+	request  models.Node
+	location string
+	actions  []string
+	// The node reports Status for pendingPolls more reads, then settles on
+	// nextStatus.
+	pendingPolls int
+	nextStatus   string
+	// An accepted action or upgrade runs start after startPolls more reads.
+	start      func()
+	startPolls int
+}
+
+type Server struct {
+	*httptest.Server
+
+	// TransitionPolls is the number of reads a node stays in a transitional
+	// status (Creating, Stopping, ...) before settling.
+	TransitionPolls int
+	// ActionStartPolls is the number of reads a node keeps its status after
+	// an action is accepted, before it starts.
+	ActionStartPolls int
+	// UpgradeStartPolls is the number of reads a node keeps its old plan and
+	// status after an upgrade is accepted, before it starts Upgrading.
+	UpgradeStartPolls int
+
+	mu             sync.Mutex
+	nextId         int
+	nodes          map[int]*Node
+	images         []models.Image
+	securityGroups []models.SecurityGroup
+	sshKeys        []models.SshKey
+	vpcs           []models.Vpc
+	vpcPolls       map[float64]int
+	imagePolls     map[string]int
+	requests       []*http.Request
+	failures       []failure
+}
+
+type failure struct {
+	statusCode int
+	retryAfter string
+}
+
+// New starts a fake server seeded with a default security group, an SSH key,
+// a VPC and a saved image. Close it when done.
+func New() *Server {
+	s := &Server{
+		TransitionPolls: 1,
+		nextId:          1000,
+		nodes:           map[int]*Node{},
+		vpcPolls:        map[float64]int{},
+		imagePolls:      map[string]int{},
+		images: []models.Image{{
+			Template_id:     9001,
+			Image_type:      "private",
+			Os_distribution: "Ubuntu",
+			Name:            "base-image",
+			Image_id:        "9001",
+			Distro:          "Ubuntu-22.04",
+			Sku_type:        "C2",
+			Image_state:     "Ready",
+			Creation_time:   "2023-01-02T10:00:00Z",
+		}},
+		securityGroups: []models.SecurityGroup{{
+			Id:          150,
+			Name:        "default",
+			Description: "default security group",
+			Is_default:  true,
+			Rules: []models.Rule{{
+				Id:             1,
+				Rule_type:      "Inbound",
+				Protocol_name:  "All",
+				Port_range:     "All",
+				Network:        "any",
+				Is_active:      true,
+				Security_group: 150,
+			}},
+		}},
+		sshKeys: []models.SshKey{{
+			Label:     "ops",
+			Ssh_key:   "ssh-rsa AAAAB3NzaC1yc2E ops@example.com",
+			Pk:        11,
+			Timestamp: "2023-01-02T10:00:00Z",
+		}},
+		vpcs: []models.Vpc{{
+			Created_at: "2023-01-02T10:00:00Z",
+			State:      "Active",
+			Name:       "default-vpc",
+			Ipv4_cidr:  "10.10.0.0/23",
+			Network_id: 301,
+			Gateway_ip: "10.10.0.1",
+			Pool_size:  512,
+			Is_active:  true,
+		}},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Endpoint is the value to use as the provider api_endpoint.
+func (s *Server) Endpoint() string {
+	return s.URL + "/myaccount/api/v1/"
+}
+
+// FailNext makes the next count requests fail with statusCode. A non empty
+// retryAfter is sent as the Retry-After header.
+func (s *Server) FailNext(count int, statusCode int, retryAfter string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < count; i++ {
+		s.failures = append(s.failures, failure{statusCode: statusCode, retryAfter: retryAfter})
+	}
+}
+
+// Requests returns every request received so far.
+func (s *Server) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// Node returns a copy of the stored node, without advancing its status.
+func (s *Server) Node(id int) (Node, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	node, ok := s.nodes[id]
+	if !ok {
+		return Node{}, false
+	}
+	return *node, true
+}
+
+// Image returns the saved image with the given image id.
+func (s *Server) Image(imageId string) (models.Image, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, image := range s.images {
+		if image.Image_id == imageId {
+			return image, true
+		}
+	}
+	return models.Image{}, false
+}
+
+// SecurityGroup returns the security group with the given id.
+func (s *Server) SecurityGroup(id int) (models.SecurityGroup, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, group := range s.securityGroups {
+		if int(group.Id) == id {
+			return group, true
+		}
+	}
+	return models.SecurityGroup{}, false
+}
+
+// AddImage stores a saved image as if it had been created from the console.
+func (s *Server) AddImage(image models.Image) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.images = append(s.images, image)
+}
+
+// SetNode replaces a stored node, for example to simulate changes made from
+// the console.
+func (s *Server) SetNode(node Node) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := node
+	s.nodes[node.Id] = &stored
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+
+	if len(s.failures) > 0 {
+		f := s.failures[0]
+		s.failures = s.failures[1:]
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		writeError(w, f.statusCode, http.StatusText(f.statusCode))
+		return
+	}
+
+	if r.URL.Query().Get("apikey") != APIKey || r.Header.Get("Authorization") != "Bearer "+AuthToken {
+		writeError(w, http.StatusUnauthorized, "Authentication credentials were not provided")
+		return
+	}
+
+	if !validLocation(requestLocation(r)) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid location %s", requestLocation(r)))
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/myaccount/api/v1/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case path == "nodes/" && r.Method == http.MethodPost:
+		s.createNode(w, r)
+	case path == "nodes/" && r.Method == http.MethodGet:
+		s.listNodes(w, r)
+	case len(parts) == 2 && parts[0] == "nodes" && r.Method == http.MethodGet:
+		s.getNode(w, r, parts[1])
+	case len(parts) == 2 && parts[0] == "nodes" && r.Method == http.MethodDelete:
+		s.deleteNode(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "nodes" && parts[2] == "actions" && r.Method == http.MethodPost:
+		s.nodeAction(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "nodes" && parts[1] == "upgrade" && r.Method == http.MethodPost:
+		s.upgradeNode(w, r, parts[2])
+	case path == "plans/" && r.Method == http.MethodGet:
+		s.listPlans(w, r)
+	case path == "images/os-category/" && r.Method == http.MethodGet:
+		s.listOsImages(w)
+	case path == "images/saved-images/" && r.Method == http.MethodGet:
+		s.listSavedImages(w)
+	case len(parts) == 2 && parts[0] == "images" && r.Method == http.MethodDelete:
+		s.deleteSavedImage(w, parts[1])
+	case path == "security_group/" && r.Method == http.MethodGet:
+		writeData(w, s.securityGroups)
+	case path == "security_group/" && r.Method == http.MethodPost:
+		s.createSecurityGroup(w, r)
+	case len(parts) == 2 && parts[0] == "security_group" && r.Method == http.MethodGet:
+		s.getSecurityGroup(w, parts[1])
+	case len(parts) == 2 && parts[0] == "security_group" && r.Method == http.MethodPut:
+		s.updateSecurityGroup(w, r, parts[1])
+	case len(parts) == 2 && parts[0] == "security_group" && r.Method == http.MethodDelete:
+		s.deleteSecurityGroup(w, parts[1])
+	case len(parts) == 3 && parts[0] == "security_group" && parts[2] == "attach" && r.Method == http.MethodPost:
+		s.attachSecurityGroups(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "security_group" && parts[2] == "detach" && r.Method == http.MethodPost:
+		s.detachSecurityGroups(w, r, parts[1])
+	case path == "ssh_keys/" && r.Method == http.MethodGet:
+		writeData(w, s.sshKeys)
+	case path == "ssh_keys/" && r.Method == http.MethodPost:
+		s.createSshKey(w, r)
+	case len(parts) == 2 && parts[0] == "ssh_keys" && r.Method == http.MethodGet:
+		s.getSshKey(w, parts[1])
+	case len(parts) == 2 && parts[0] == "ssh_keys" && r.Method == http.MethodDelete:
+		s.deleteSshKey(w, parts[1])
+	case path == "vpc/list/" && r.Method == http.MethodGet:
+		s.listVpcs(w)
+	case path == "vpc/node/attach/" && r.Method == http.MethodPost:
+		s.nodeVpcAction(w, r)
+	case path == "vpc/" && r.Method == http.MethodPost:
+		s.createVpc(w, r)
+	case len(parts) == 2 && parts[0] == "vpc" && r.Method == http.MethodGet:
+		s.getVpc(w, parts[1])
+	case len(parts) == 2 && parts[0] == "vpc" && r.Method == http.MethodDelete:
+		s.deleteVpc(w, parts[1])
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) createNode(w http.ResponseWriter, r *http.Request) {
+	request := models.Node{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if request.Name == "" || request.Plan == "" || request.Image == "" {
+		writeError(w, http.StatusBadRequest, "name, plan and image are required")
+		return
+	}
+	if request.Is_saved_image && !s.hasTemplate(request.Saved_image_template_id) {
+		writeError(w, http.StatusBadRequest, "saved_image_template_id is not a saved image")
+		return
+	}
+
+	securityGroupId := request.Security_group_id
+	if securityGroupId == 0 {
+		securityGroupId = s.defaultSecurityGroupId()
+	}
+	if !s.hasSecurityGroup(securityGroupId) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Security group %d not found", securityGroupId))
+		return
+	}
+
+	sshKeys := []string{}
+	for _, key := range request.SSH_keys {
+		sshKeys = append(sshKeys, fmt.Sprint(key))
+	}
+
+	s.nextId++
+	node := &Node{
+		NodeDetail: models.NodeDetail{
+			Id:                 s.nextId,
+			Name:               request.Name,
+			Label:              request.Label,
+			Plan:               request.Plan,
+			Status:             "Creating",
+			Image:              stringPtr(request.Image),
+			Region:             stringPtr(request.Region),
+			Vpc_id:             request.Vpc_id,
+			SSH_keys:           sshKeys,
+			Backup:             boolPtr(request.Backup),
+			Default_public_ip:  boolPtr(request.Default_public_ip),
+			Disable_password:   boolPtr(request.Disable_password),
+			Is_ipv6_availed:    boolPtr(request.Is_ipv6_availed),
+			Is_active:          true,
+			Created_at:         time.Now().UTC().Format(time.RFC3339),
+			Memory:             "40 GB",
+			Disk:               "100 GB",
+			Price:              "3.5",
+			Private_ip_address: fmt.Sprintf("10.10.0.%d", s.nextId%250+2),
+		},
+		request:  request,
+		location: requestLocation(r),
+	}
+	// Like the API, report an unused reserve_ip as null.
+	if request.Reserve_ip != "" {
+		node.Reserve_ip = stringPtr(request.Reserve_ip)
+	}
+	s.setSecurityGroups(node, []int{securityGroupId})
+	if request.Vpc_id != "" {
+		s.attachVpc(node, request.Vpc_id)
+	}
+	s.transition(node, "Creating", "Running")
+	s.nodes[node.Id] = node
+	writeData(w, node)
+}
+
+func (s *Server) listNodes(w http.ResponseWriter, r *http.Request) {
+	ids := []int{}
+	for id, node := range s.nodes {
+		if node.location == requestLocation(r) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page_no"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+	nodes := []models.NodeDetail{}
+	for i := (page - 1) * perPage; i < len(ids) && i < page*perPage; i++ {
+		nodes = append(nodes, s.nodes[ids[i]].NodeDetail)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code":              http.StatusOK,
+		"data":              nodes,
+		"errors":            map[string]interface{}{},
+		"message":           "Success",
+		"total_count":       len(ids),
+		"total_page_number": (len(ids) + perPage - 1) / perPage,
+	})
+}
+
+// Request returns the create request the node was ordered with.
+func (n Node) Request() models.Node {
+	return n.request
+}
+
+// Actions returns the types of the actions accepted for the node, in order.
+func (n Node) Actions() []string {
+	return append([]string(nil), n.actions...)
+}
+
+// Settled reports whether the node has no action or upgrade in progress.
+func (n Node) Settled() bool {
+	return n.start == nil && n.nextStatus == ""
+}
+
+// findNode looks a node up in the location of the request, nodes created in
+// another location are reported as missing.
+func (s *Server) findNode(w http.ResponseWriter, r *http.Request, id string) *Node {
+	nodeId, err := strconv.Atoi(id)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid node id")
+		return nil
+	}
+	node, ok := s.nodes[nodeId]
+	if !ok || node.location != requestLocation(r) {
+		writeError(w, http.StatusNotFound, "Node not found")
+		return nil
+	}
+	return node
+}
+
+func (s *Server) getNode(w http.ResponseWriter, r *http.Request, id string) {
+	node := s.findNode(w, r, id)
+	if node == nil {
+		return
+	}
+	if node.start != nil {
+		if node.startPolls--; node.startPolls <= 0 {
+			start := node.start
+			node.start = nil
+			start()
+		}
+	} else if node.pendingPolls > 0 {
+		node.pendingPolls--
+	} else if node.nextStatus != "" {
+		node.Status = node.nextStatus
+		node.nextStatus = ""
+		if node.Status == "Running" && node.Public_ip_address == "" {
+			node.Public_ip_address = fmt.Sprintf("164.52.0.%d", node.Id%250+2)
+		}
+	}
+	writeData(w, node)
+}
+
+func (s *Server) deleteNode(w http.ResponseWriter, r *http.Request, id string) {
+	node := s.findNode(w, r, id)
+	if node == nil {
+		return
+	}
+	if node.Is_locked {
+		writeError(w, http.StatusBadRequest, "Node is locked")
+		return
+	}
+	delete(s.nodes, node.Id)
+	writeData(w, map[string]interface{}{})
+}
+
+func (s *Server) nodeAction(w http.ResponseWriter, r *http.Request, id string) {
+	node := s.findNode(w, r, id)
+	if node == nil {
+		return
+	}
+	action := models.NodeAction{}
+	if err := json.NewDecoder(r.Body).Decode(&action); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch action.Type {
+	case "lock_vm":
+		node.Is_locked = true
+	case "unlock_vm":
+		node.Is_locked = false
+	case "power_on", "power_off", "reboot", "reinstall":
+		if node.Is_locked {
+			writeError(w, http.StatusBadRequest, "Node is locked, unlock the node to perform this action")
+			return
+		}
+		if node.nextStatus != "" {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Node is in %s state", node.Status))
+			return
+		}
+		if node.start != nil {
+			writeError(w, http.StatusConflict, "Node has an action in progress")
+			return
+		}
+		pending, target := "", ""
+		switch action.Type {
+		case "power_on":
+			pending, target = "Starting", "Running"
+		case "power_off":
+			pending, target = "Stopping", "Powered off"
+		case "reboot":
+			if node.Status != "Running" {
+				writeError(w, http.StatusBadRequest, "Node should be running to reboot")
+				return
+			}
+			pending, target = "Rebooting", "Running"
+		case "reinstall":
+			pending, target = "Reinstalling", "Running"
+		}
+		s.queue(node, s.ActionStartPolls, func() {
+			s.transition(node, pending, target)
+		})
+	case "save_images":
+		if node.Status != "Powered off" {
+			writeError(w, http.StatusBadRequest, "Node should be powered off to save image")
+			return
+		}
+		s.saveImage(node, action.Name)
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown action %s", action.Type))
+		return
+	}
+	node.actions = append(node.actions, action.Type)
+	writeData(w, map[string]interface{}{"id": node.Id, "action_type": action.Type, "status": "Done"})
+}
+
+// upgradePaths lists, for each plan, the plans a node can be resized to.
+var upgradePaths = map[string][]string{
+	"C2.40GB":  {"C2.80GB", "C2.120GB"},
+	"C2.80GB":  {"C2.120GB"},
+	"C2.120GB": {},
+}
+
+func (s *Server) upgradeNode(w http.ResponseWriter, r *http.Request, id string) {
+	node := s.findNode(w, r, id)
+	if node == nil {
+		return
+	}
+	request := models.NodeUpgrade{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if node.Is_locked {
+		writeError(w, http.StatusBadRequest, "Node is locked, unlock the node to perform this action")
+		return
+	}
+	if node.Status != "Powered off" {
+		writeError(w, http.StatusBadRequest, "Node should be powered off to upgrade")
+		return
+	}
+	valid := false
+	for _, plan := range upgradePaths[node.Plan] {
+		valid = valid || plan == request.Plan
+	}
+	if !valid {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Plan %s is not available as an upgrade of %s for image %s", request.Plan, node.Plan, request.Image))
+		return
+	}
+	s.queue(node, s.UpgradeStartPolls, func() {
+		node.Plan = request.Plan
+		s.transition(node, "Upgrading", "Powered off")
+	})
+	writeData(w, map[string]interface{}{"id": node.Id, "plan": request.Plan})
+}
+
+// requestLocation returns the location a request is sent to, the API falls
+// back to Delhi when none is given.
+func requestLocation(r *http.Request) string {
+	if location := r.URL.Query().Get("location"); location != "" {
+		return location
+	}
+	return "Delhi"
+}
+
+func validLocation(location string) bool {
+	for _, known := range constants.Locations {
+		if location == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) transition(node *Node, pending string, target string) {
+	node.Status = pending
+	node.nextStatus = target
+	node.pendingPolls = s.TransitionPolls
+}
+
+// queue runs start now, or after polls more reads of the node when polls is
+// not zero.
+func (s *Server) queue(node *Node, polls int, start func()) {
+	if polls == 0 {
+		start()
+		return
+	}
+	node.start = start
+	node.startPolls = polls
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code":    http.StatusOK,
+		"data":    data,
+		"errors":  map[string]interface{}{},
+		"message": "Success",
+	})
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"code":    statusCode,
+		"data":    map[string]interface{}{},
+		"errors":  message,
+		"message": message,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
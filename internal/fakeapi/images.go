@@ -0,0 +1,85 @@
+package fakeapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+)
+
+var distroImages = []string{"Ubuntu-22.04-Distro", "Ubuntu-20.04-Distro", "CentOS-7.5-Distro"}
+
+func (s *Server) listOsImages(w http.ResponseWriter) {
+	images := []models.OsImage{}
+	for _, image := range distroImages {
+		parts := strings.Split(image, "-")
+		osImage := models.OsImage{
+			Os:        parts[0],
+			Version:   parts[1],
+			Image:     image,
+			Plans:     []string{},
+			Locations: []string{"Delhi", "Mumbai"},
+		}
+		for _, plan := range plans {
+			if contains(plan.Images, image) {
+				osImage.Plans = append(osImage.Plans, plan.Name)
+			}
+		}
+		images = append(images, osImage)
+	}
+	writeData(w, images)
+}
+
+// saveImage stores an image of the node. It is listed as Creating for
+// TransitionPolls listings before becoming Ready.
+func (s *Server) saveImage(node *Node, name string) {
+	s.nextId++
+	image := models.Image{
+		Template_id:     float64(s.nextId),
+		Image_type:      "private",
+		Os_distribution: strings.Split(node.request.Image, "-")[0],
+		Name:            name,
+		Image_id:        strconv.Itoa(s.nextId),
+		Distro:          node.request.Image,
+		Sku_type:        node.Plan,
+		Image_state:     "Creating",
+		Image_size:      node.Disk,
+		Creation_time:   time.Now().UTC().Format(time.RFC3339),
+	}
+	s.images = append(s.images, image)
+	s.imagePolls[image.Image_id] = s.TransitionPolls
+}
+
+func (s *Server) listSavedImages(w http.ResponseWriter) {
+	for i := range s.images {
+		image := &s.images[i]
+		if s.imagePolls[image.Image_id] > 0 {
+			s.imagePolls[image.Image_id]--
+		} else if image.Image_state == "Creating" {
+			image.Image_state = "Ready"
+		}
+	}
+	writeData(w, s.images)
+}
+
+func (s *Server) deleteSavedImage(w http.ResponseWriter, imageId string) {
+	for i, image := range s.images {
+		if image.Image_id == imageId {
+			s.images = append(s.images[:i], s.images[i+1:]...)
+			writeData(w, map[string]interface{}{})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Image not found")
+}
+
+func (s *Server) hasTemplate(templateId int) bool {
+	for _, image := range s.images {
+		if int(image.Template_id) == templateId {
+			return true
+		}
+	}
+	return false
+}
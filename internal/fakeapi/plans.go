@@ -0,0 +1,43 @@
+package fakeapi
+
+import (
+	"net/http"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+)
+
+// plans is the catalog served in every location. GPU plans are only offered
+// in Delhi and only run Ubuntu.
+var plans = []models.Plan{
+	{Name: "C2.40GB", Series: "C2", Cpu: 12, Memory: 40, Disk: 100, Price_per_hour: 4.8, Price_per_month: 3504, Images: distroImages},
+	{Name: "C2.80GB", Series: "C2", Cpu: 24, Memory: 80, Disk: 200, Price_per_hour: 9.6, Price_per_month: 7008, Images: distroImages},
+	{Name: "C2.120GB", Series: "C2", Cpu: 32, Memory: 120, Disk: 300, Price_per_hour: 14.4, Price_per_month: 10512, Images: distroImages},
+	{Name: "GDC.A100-16.115GB", Series: "GPU", Location: "Delhi", Cpu: 16, Memory: 115, Disk: 1500, Gpu: 1, Price_per_hour: 226, Price_per_month: 164980, Images: distroImages[:2]},
+}
+
+func (s *Server) listPlans(w http.ResponseWriter, r *http.Request) {
+	location := requestLocation(r)
+	image := r.URL.Query().Get("image")
+
+	matched := []models.Plan{}
+	for _, plan := range plans {
+		if plan.Location != "" && plan.Location != location {
+			continue
+		}
+		if image != "" && !contains(plan.Images, image) {
+			continue
+		}
+		plan.Location = location
+		matched = append(matched, plan)
+	}
+	writeData(w, matched)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
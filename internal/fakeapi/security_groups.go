@@ -0,0 +1,235 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+)
+
+func (s *Server) createSecurityGroup(w http.ResponseWriter, r *http.Request) {
+	request := models.AddSecurityGroup{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if request.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	s.nextId++
+	s.securityGroups = append(s.securityGroups, models.SecurityGroup{Id: float64(s.nextId)})
+	group := &s.securityGroups[len(s.securityGroups)-1]
+	s.applySecurityGroup(group, request)
+	writeData(w, group)
+}
+
+func (s *Server) findSecurityGroup(w http.ResponseWriter, id string) *models.SecurityGroup {
+	for i := range s.securityGroups {
+		if strconv.Itoa(int(s.securityGroups[i].Id)) == id {
+			return &s.securityGroups[i]
+		}
+	}
+	writeError(w, http.StatusNotFound, "Security group not found")
+	return nil
+}
+
+func (s *Server) getSecurityGroup(w http.ResponseWriter, id string) {
+	if group := s.findSecurityGroup(w, id); group != nil {
+		writeData(w, group)
+	}
+}
+
+func (s *Server) updateSecurityGroup(w http.ResponseWriter, r *http.Request, id string) {
+	group := s.findSecurityGroup(w, id)
+	if group == nil {
+		return
+	}
+	request := models.AddSecurityGroup{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.applySecurityGroup(group, request)
+	writeData(w, group)
+}
+
+func (s *Server) deleteSecurityGroup(w http.ResponseWriter, id string) {
+	group := s.findSecurityGroup(w, id)
+	if group == nil {
+		return
+	}
+	if group.Is_default {
+		writeError(w, http.StatusBadRequest, "Default security group cannot be deleted")
+		return
+	}
+	for _, node := range s.nodes {
+		for _, attached := range node.Security_groups {
+			if attached.Id == int(group.Id) {
+				writeError(w, http.StatusBadRequest, "Security group is attached to a node")
+				return
+			}
+		}
+	}
+	for i := range s.securityGroups {
+		if s.securityGroups[i].Id == group.Id {
+			s.securityGroups = append(s.securityGroups[:i], s.securityGroups[i+1:]...)
+			break
+		}
+	}
+	writeData(w, map[string]interface{}{})
+}
+
+// applySecurityGroup replaces the group with request. Every rule gets a new
+// id, nothing says the API keeps them across updates.
+func (s *Server) applySecurityGroup(group *models.SecurityGroup, request models.AddSecurityGroup) {
+	if request.Default {
+		for i := range s.securityGroups {
+			s.securityGroups[i].Is_default = false
+		}
+	}
+	group.Name = request.Name
+	group.Description = request.Description
+	group.Is_default = request.Default
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	rules := make([]models.Rule, 0, len(request.Rules))
+	for _, add := range request.Rules {
+		rule := models.Rule{
+			Rule_type:      add.Rule_type,
+			Protocol_name:  add.Protocol_name,
+			Port_range:     add.Port_range,
+			Network:        add.Network,
+			Network_cidr:   add.Network_cidr,
+			Is_active:      true,
+			Created_at:     now,
+			Updated_at:     now,
+			Security_group: group.Id,
+		}
+		s.nextId++
+		rule.Id = float64(s.nextId)
+		rules = append(rules, rule)
+	}
+	group.Rules = rules
+}
+
+func (s *Server) defaultSecurityGroupId() int {
+	for _, group := range s.securityGroups {
+		if group.Is_default {
+			return int(group.Id)
+		}
+	}
+	return 0
+}
+
+func (s *Server) hasSecurityGroup(id int) bool {
+	for _, group := range s.securityGroups {
+		if int(group.Id) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// setSecurityGroups replaces the groups attached to node. The first one is
+// reported as the security_group_id of the node.
+func (s *Server) setSecurityGroups(node *Node, ids []int) {
+	node.Security_groups = []models.NodeSecurityGroup{}
+	for _, id := range ids {
+		for _, group := range s.securityGroups {
+			if int(group.Id) == id {
+				node.Security_groups = append(node.Security_groups, models.NodeSecurityGroup{
+					Id:         id,
+					Name:       group.Name,
+					Is_default: group.Is_default,
+				})
+			}
+		}
+	}
+	node.Security_group_id = ids[0]
+}
+
+func (s *Server) decodeNodeSecurityGroups(w http.ResponseWriter, r *http.Request) ([]int, bool) {
+	request := models.NodeSecurityGroups{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if len(request.Security_group_ids) == 0 {
+		writeError(w, http.StatusBadRequest, "security_group_ids is required")
+		return nil, false
+	}
+	for _, id := range request.Security_group_ids {
+		if !s.hasSecurityGroup(id) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Security group %d not found", id))
+			return nil, false
+		}
+	}
+	return request.Security_group_ids, true
+}
+
+func (s *Server) attachSecurityGroups(w http.ResponseWriter, r *http.Request, nodeId string) {
+	node := s.findNode(w, r, nodeId)
+	if node == nil {
+		return
+	}
+	ids, ok := s.decodeNodeSecurityGroups(w, r)
+	if !ok {
+		return
+	}
+
+	attached := []int{}
+	for _, group := range node.Security_groups {
+		attached = append(attached, group.Id)
+	}
+	for _, id := range ids {
+		if containsInt(attached, id) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Security group %d is already attached to the node", id))
+			return
+		}
+		attached = append(attached, id)
+	}
+	s.setSecurityGroups(node, attached)
+	writeData(w, map[string]interface{}{})
+}
+
+func (s *Server) detachSecurityGroups(w http.ResponseWriter, r *http.Request, nodeId string) {
+	node := s.findNode(w, r, nodeId)
+	if node == nil {
+		return
+	}
+	ids, ok := s.decodeNodeSecurityGroups(w, r)
+	if !ok {
+		return
+	}
+
+	remaining := []int{}
+	for _, group := range node.Security_groups {
+		if !containsInt(ids, group.Id) {
+			remaining = append(remaining, group.Id)
+		}
+	}
+	if len(node.Security_groups)-len(remaining) != len(ids) {
+		writeError(w, http.StatusBadRequest, "Security group is not attached to the node")
+		return
+	}
+	if len(remaining) == 0 {
+		writeError(w, http.StatusBadRequest, "A node must have at least one security group")
+		return
+	}
+	s.setSecurityGroups(node, remaining)
+	writeData(w, map[string]interface{}{})
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+)
+
+func (s *Server) createSshKey(w http.ResponseWriter, r *http.Request) {
+	request := models.AddSshKey{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if request.Label == "" || len(strings.Fields(request.Ssh_key)) < 2 {
+		writeError(w, http.StatusBadRequest, "label and a valid ssh_key are required")
+		return
+	}
+	for _, key := range s.sshKeys {
+		if key.Label == request.Label {
+			writeError(w, http.StatusBadRequest, "An SSH key with this label already exists")
+			return
+		}
+	}
+
+	s.nextId++
+	key := models.SshKey{
+		Label:     request.Label,
+		Ssh_key:   request.Ssh_key,
+		Pk:        s.nextId,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	s.sshKeys = append(s.sshKeys, key)
+	writeData(w, key)
+}
+
+func (s *Server) findSshKey(w http.ResponseWriter, pk string) int {
+	for i, key := range s.sshKeys {
+		if strconv.Itoa(key.Pk) == pk {
+			return i
+		}
+	}
+	writeError(w, http.StatusNotFound, "SSH key not found")
+	return -1
+}
+
+func (s *Server) getSshKey(w http.ResponseWriter, pk string) {
+	if i := s.findSshKey(w, pk); i >= 0 {
+		writeData(w, s.sshKeys[i])
+	}
+}
+
+func (s *Server) deleteSshKey(w http.ResponseWriter, pk string) {
+	if i := s.findSshKey(w, pk); i >= 0 {
+		s.sshKeys = append(s.sshKeys[:i], s.sshKeys[i+1:]...)
+		writeData(w, map[string]interface{}{})
+	}
+}
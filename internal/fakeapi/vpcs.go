@@ -0,0 +1,184 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/e2eterraformprovider/terraform-provider-e2e/models"
+)
+
+func (s *Server) vpcNodeCount(vpc models.Vpc) int {
+	count := 0
+	for _, node := range s.nodes {
+		for _, attached := range node.Vpcs {
+			if attached.Network_id == int(vpc.Network_id) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func (s *Server) listVpcs(w http.ResponseWriter) {
+	vpcs := make([]models.Vpc, len(s.vpcs))
+	for i, vpc := range s.vpcs {
+		vpc.Vm_count = s.vpcNodeCount(vpc)
+		vpcs[i] = vpc
+	}
+	writeData(w, vpcs)
+}
+
+func (s *Server) createVpc(w http.ResponseWriter, r *http.Request) {
+	request := models.AddVpc{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if request.Name == "" {
+		writeError(w, http.StatusBadRequest, "vpc_name is required")
+		return
+	}
+	s.nextId++
+	cidr := fmt.Sprintf("10.%d.0.0/23", s.nextId%250)
+	if request.Ipv4 != "" {
+		cidr = request.Ipv4
+	}
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid ipv4 cidr")
+		return
+	}
+	gateway := ip.To4()
+	gateway[3]++
+
+	vpc := models.Vpc{
+		Created_at: time.Now().UTC().Format(time.RFC3339),
+		State:      "Creating",
+		Name:       request.Name,
+		Ipv4_cidr:  cidr,
+		Network_id: float64(s.nextId),
+		Gateway_ip: gateway.String(),
+		Pool_size:  512,
+		Is_active:  true,
+	}
+	s.vpcs = append(s.vpcs, vpc)
+	s.vpcPolls[vpc.Network_id] = s.TransitionPolls
+	writeData(w, vpc)
+}
+
+func (s *Server) findVpc(w http.ResponseWriter, networkId string) int {
+	for i, vpc := range s.vpcs {
+		if strconv.Itoa(int(vpc.Network_id)) == networkId {
+			return i
+		}
+	}
+	writeError(w, http.StatusNotFound, "VPC not found")
+	return -1
+}
+
+func (s *Server) getVpc(w http.ResponseWriter, networkId string) {
+	i := s.findVpc(w, networkId)
+	if i < 0 {
+		return
+	}
+	vpc := &s.vpcs[i]
+	if s.vpcPolls[vpc.Network_id] > 0 {
+		s.vpcPolls[vpc.Network_id]--
+	} else if vpc.State == "Creating" {
+		vpc.State = "Active"
+	}
+	detail := *vpc
+	detail.Vm_count = s.vpcNodeCount(detail)
+	writeData(w, detail)
+}
+
+func (s *Server) deleteVpc(w http.ResponseWriter, networkId string) {
+	i := s.findVpc(w, networkId)
+	if i < 0 {
+		return
+	}
+	if s.vpcNodeCount(s.vpcs[i]) > 0 {
+		writeError(w, http.StatusBadRequest, "VPC is attached to nodes")
+		return
+	}
+	s.vpcs = append(s.vpcs[:i], s.vpcs[i+1:]...)
+	writeData(w, map[string]interface{}{})
+}
+
+// attachVpc adds the vpc to the node with a private IP taken from the vpc
+// range. Unknown vpcs are ignored, like the create endpoint does.
+func (s *Server) attachVpc(node *Node, networkId string) {
+	for _, vpc := range s.vpcs {
+		if strconv.Itoa(int(vpc.Network_id)) != networkId {
+			continue
+		}
+		ip := net.ParseIP(vpc.Gateway_ip).To4()
+		ip[3] = byte(node.Id%250 + 2)
+		node.Vpcs = append(node.Vpcs, models.NodeVpc{
+			Network_id: int(vpc.Network_id),
+			Name:       vpc.Name,
+			Private_ip: ip.String(),
+		})
+		if node.Vpc_id == "" {
+			node.Vpc_id = networkId
+		}
+	}
+}
+
+func (s *Server) nodeVpcAction(w http.ResponseWriter, r *http.Request) {
+	request := models.NodeVpcAction{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	node := s.findNode(w, r, strconv.Itoa(request.Node_id))
+	if node == nil {
+		return
+	}
+	networkId := strconv.Itoa(request.Network_id)
+	i := s.findVpc(w, networkId)
+	if i < 0 {
+		return
+	}
+	if node.Is_locked {
+		writeError(w, http.StatusBadRequest, "Node is locked, unlock the node to perform this action")
+		return
+	}
+
+	attached := -1
+	for j, vpc := range node.Vpcs {
+		if vpc.Network_id == request.Network_id {
+			attached = j
+		}
+	}
+	switch request.Action {
+	case "attach":
+		if attached >= 0 {
+			writeError(w, http.StatusBadRequest, "Node is already attached to the VPC")
+			return
+		}
+		if s.vpcs[i].State != "Active" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("VPC is in %s state", s.vpcs[i].State))
+			return
+		}
+		s.attachVpc(node, networkId)
+	case "detach":
+		if attached < 0 {
+			writeError(w, http.StatusBadRequest, "Node is not attached to the VPC")
+			return
+		}
+		node.Vpcs = append(node.Vpcs[:attached], node.Vpcs[attached+1:]...)
+		node.Vpc_id = ""
+		if len(node.Vpcs) > 0 {
+			node.Vpc_id = strconv.Itoa(node.Vpcs[0].Network_id)
+		}
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown action %s", request.Action))
+		return
+	}
+	writeData(w, map[string]interface{}{"message": "Success"})
+}
@@ -26,3 +26,18 @@ type Image struct {
 	Creation_time       string        `json:"creation_time"`
 	Auto_scale_template bool          `json:"auto_scale_template"`
 }
+
+type OsImageListResponse struct {
+	Code    int         `json:"code"`
+	Data    []OsImage   `json:"data"`
+	Errors  interface{} `json:"errors"`
+	Message string      `json:"message"`
+}
+
+type OsImage struct {
+	Os        string   `json:"os"`
+	Version   string   `json:"version"`
+	Image     string   `json:"image"`
+	Plans     []string `json:"plans"`
+	Locations []string `json:"locations"`
+}
@@ -6,7 +6,7 @@ type Node struct {
 	Plan                    string        `json:"plan"`
 	Backup                  bool          `json:"backup"`
 	Image                   string        `json:"image"`
-	Default_public_ip       bool          `json:"default_public_id"`
+	Default_public_ip       bool          `json:"default_public_ip"`
 	Disable_password        bool          `json:"disable_password"`
 	Enable_bitninja         bool          `json:"enable_bitninja"`
 	Is_ipv6_availed         bool          `json:"is_ipv6_availed"`
@@ -14,12 +14,111 @@ type Node struct {
 	Region                  string        `json:"region"`
 	Reserve_ip              string        `json:"reserve_ip"`
 	Vpc_id                  string        `json:"vpc_id"`
-	Ngc_container_id        int           `json:"ngc_container_id"`
-	Saved_image_template_id int           `json:"saved_image_template_id"`
+	Ngc_container_id        int           `json:"ngc_container_id,omitempty"`
+	Saved_image_template_id int           `json:"saved_image_template_id,omitempty"`
 	Security_group_id       int           `json:"security_group_id"`
 	SSH_keys                []interface{} `json:"ssh_keys"`
+	User_data               string        `json:"user_data,omitempty"`
 }
 type NodeAction struct {
 	Type string `json:"type"`
 	Name string `json:"name"`
 }
+
+type NodeUpgrade struct {
+	Plan  string `json:"plan"`
+	Image string `json:"image"`
+}
+
+// NodeDetail is a node as returned by the API. The create time arguments the
+// API may report as null or leave out (image, region, reserve_ip and the
+// flags) are pointers, nil when no value was returned. Other fields reported
+// as null (public_ip_address before an IP is assigned, vpc_id when unused,
+// ...) decode to their zero value.
+//
+// Security_groups and Vpcs list everything attached to the node, including
+// the groups and vpc reported in Security_group_id and Vpc_id.
+type NodeDetail struct {
+	Id                         int                 `json:"id"`
+	Name                       string              `json:"name"`
+	Label                      string              `json:"label"`
+	Plan                       string              `json:"plan"`
+	Image                      *string             `json:"image"`
+	Status                     string              `json:"status"`
+	Region                     *string             `json:"region"`
+	Vpc_id                     string              `json:"vpc_id"`
+	Vpcs                       []NodeVpc           `json:"vpcs"`
+	Security_group_id          int                 `json:"security_group_id"`
+	Security_groups            []NodeSecurityGroup `json:"security_groups"`
+	SSH_keys                   []string            `json:"ssh_keys"`
+	Reserve_ip                 *string             `json:"reserve_ip"`
+	Backup                     *bool               `json:"backup"`
+	Default_public_ip          *bool               `json:"default_public_ip"`
+	Disable_password           *bool               `json:"disable_password"`
+	Is_ipv6_availed            *bool               `json:"is_ipv6_availed"`
+	Is_locked                  bool                `json:"is_locked"`
+	Is_active                  bool                `json:"is_active"`
+	Is_monitored               bool                `json:"is_monitored"`
+	Is_bitninja_license_active bool                `json:"is_bitninja_license_active"`
+	Created_at                 string              `json:"created_at"`
+	Memory                     string              `json:"memory"`
+	Disk                       string              `json:"disk"`
+	Price                      string              `json:"price"`
+	Public_ip_address          string              `json:"public_ip_address"`
+	Private_ip_address         string              `json:"private_ip_address"`
+}
+
+type NodeSecurityGroup struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name"`
+	Is_default bool   `json:"is_default"`
+}
+
+// NodeSecurityGroups is the body of the security group attach and detach
+// requests of a node.
+type NodeSecurityGroups struct {
+	Security_group_ids []int `json:"security_group_ids"`
+}
+
+type NodeVpc struct {
+	Network_id int    `json:"network_id"`
+	Name       string `json:"name"`
+	Private_ip string `json:"private_ip"`
+}
+
+// NodeVpcAction is the body of the vpc attach and detach requests of a node.
+type NodeVpcAction struct {
+	Action     string `json:"action"`
+	Network_id int    `json:"network_id"`
+	Node_id    int    `json:"node_id"`
+}
+
+type NodeResponse struct {
+	Code    int         `json:"code"`
+	Data    NodeDetail  `json:"data"`
+	Errors  interface{} `json:"errors"`
+	Message string      `json:"message"`
+}
+
+type ActionResponse struct {
+	Code    int          `json:"code"`
+	Data    ActionDetail `json:"data"`
+	Errors  interface{}  `json:"errors"`
+	Message string       `json:"message"`
+}
+
+type ActionDetail struct {
+	Id          int    `json:"id"`
+	Action_type string `json:"action_type"`
+	Status      string `json:"status"`
+	Created_at  string `json:"created_at"`
+}
+
+type NodeListResponse struct {
+	Code              int          `json:"code"`
+	Data              []NodeDetail `json:"data"`
+	Errors            interface{}  `json:"errors"`
+	Message           string       `json:"message"`
+	Total_count       int          `json:"total_count"`
+	Total_page_number int          `json:"total_page_number"`
+}
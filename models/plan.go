@@ -0,0 +1,21 @@
+package models
+
+type PlanListResponse struct {
+	Code    int         `json:"code"`
+	Data    []Plan      `json:"data"`
+	Errors  interface{} `json:"errors"`
+	Message string      `json:"message"`
+}
+
+type Plan struct {
+	Name            string   `json:"plan"`
+	Series          string   `json:"series"`
+	Location        string   `json:"location"`
+	Cpu             int      `json:"cpu"`
+	Memory          float64  `json:"ram"`
+	Disk            int      `json:"disk_space"`
+	Gpu             int      `json:"gpu"`
+	Price_per_hour  float64  `json:"price_per_hour"`
+	Price_per_month float64  `json:"price_per_month"`
+	Images          []string `json:"images"`
+}
@@ -28,3 +28,25 @@ type Rule struct {
 	Network_size   float64 `json:"network_size"`
 	Security_group float64 `json:"security_group"`
 }
+
+type AddSecurityGroup struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Default     bool      `json:"default"`
+	Rules       []AddRule `json:"rules"`
+}
+
+type AddRule struct {
+	Rule_type     string `json:"rule_type"`
+	Protocol_name string `json:"protocol_name"`
+	Port_range    string `json:"port_range"`
+	Network       string `json:"network"`
+	Network_cidr  string `json:"network_cidr"`
+}
+
+type SingleSecurityGroupResponse struct {
+	Code    int           `json:"code"`
+	Data    SecurityGroup `json:"data"`
+	Error   interface{}   `json:"error"`
+	Message string        `json:"message"`
+}
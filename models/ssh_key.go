@@ -12,3 +12,15 @@ type SshKey struct {
 	Pk        int    `json:"pk"`
 	Timestamp string `json:"timestamp"`
 }
+
+type AddSshKey struct {
+	Label   string `json:"label"`
+	Ssh_key string `json:"ssh_key"`
+}
+
+type SingleSshKeyResponse struct {
+	Code    int         `json:"code"`
+	Data    SshKey      `json:"data"`
+	Error   interface{} `json:"error"`
+	Message string      `json:"message"`
+}
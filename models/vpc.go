@@ -15,4 +15,17 @@ type Vpc struct {
 	Gateway_ip string  `json:"gateway_ip"`
 	Pool_size  float64 `json:"pool_size"`
 	Is_active  bool    `json:"is_active"`
+	Vm_count   int     `json:"vm_count"`
+}
+
+type AddVpc struct {
+	Name string `json:"vpc_name"`
+	Ipv4 string `json:"ipv4,omitempty"`
+}
+
+type SingleVpcResponse struct {
+	Code    int         `json:"code"`
+	Data    Vpc         `json:"data"`
+	Error   interface{} `json:"error"`
+	Message string      `json:"message"`
 }